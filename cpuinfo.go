@@ -0,0 +1,132 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// CPUInfoProc is a single processor block of /proc/cpuinfo. The fields
+// differ between architectures, so only the common ones are parsed and the
+// remaining ones are kept in Raw. Fields unknown to the architecture are left
+// zero.
+type CPUInfoProc struct {
+	// Number of the logical processor.
+	Processor uint
+	// Vendor of the processor, e.g. "GenuineIntel". x86 only.
+	VendorID string
+	// Model name of the processor.
+	ModelName string
+	// Current clock speed in MHz. x86 only.
+	CPUMHz float64
+	// Size of the last level cache, e.g. "8192 KB". x86 only.
+	CacheSize string
+	// Feature flags of the processor, from "Features" on ARM.
+	Flags []string
+	// Physical package and core of the logical processor. x86 only.
+	PhysicalID string
+	CoreID     string
+	// All other fields of the block, keyed by field name.
+	Raw map[string]string
+}
+
+// NewCPUInfoProc returns one entry per logical processor read from
+// /proc/cpuinfo.
+func NewCPUInfoProc() ([]CPUInfoProc, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewCPUInfoProc()
+}
+
+// NewCPUInfoProc returns one entry per logical processor read from the
+// specified `proc` filesystem.
+func (fs FS) NewCPUInfoProc() ([]CPUInfoProc, error) {
+	f, err := os.Open(fs.Path("cpuinfo"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseCPUInfoProc(f)
+}
+
+func parseCPUInfoProc(r io.Reader) ([]CPUInfoProc, error) {
+	var (
+		cpus    = []CPUInfoProc{}
+		cur     *CPUInfoProc
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			cur = nil
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("malformed cpuinfo line: %q", line)
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+
+		if key == "processor" {
+			n, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse %s (processor): %s", value, err)
+			}
+			cpus = append(cpus, CPUInfoProc{Processor: uint(n), Raw: map[string]string{}})
+			cur = &cpus[len(cpus)-1]
+			continue
+		}
+		// Blocks without a processor line, like the trailing "Hardware"
+		// block on ARM, describe the whole system and are skipped.
+		if cur == nil {
+			continue
+		}
+
+		switch key {
+		case "vendor_id":
+			cur.VendorID = value
+		case "model name":
+			cur.ModelName = value
+		case "cpu MHz":
+			mhz, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse %s (cpu MHz): %s", value, err)
+			}
+			cur.CPUMHz = mhz
+		case "cache size":
+			cur.CacheSize = value
+		case "flags", "Features":
+			cur.Flags = strings.Fields(value)
+		case "physical id":
+			cur.PhysicalID = value
+		case "core id":
+			cur.CoreID = value
+		default:
+			cur.Raw[key] = value
+		}
+	}
+
+	return cpus, scanner.Err()
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import "testing"
+
+func TestNewCPUInfoProc(t *testing.T) {
+	for _, tt := range []struct {
+		root      string
+		cpus      int
+		modelName string
+		flags     int
+		flag      string
+	}{
+		{root: "fixtures", cpus: 2, modelName: "Intel(R) Core(TM) i7-8650U CPU @ 1.90GHz", flags: 110, flag: "avx2"},
+		{root: "fixtures/arm", cpus: 2, modelName: "ARMv7 Processor rev 4 (v7l)", flags: 15, flag: "neon"},
+	} {
+		cpus, err := FS(tt.root).NewCPUInfoProc()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, have := tt.cpus, len(cpus); want != have {
+			t.Fatalf("want %s %d processors, have %d", tt.root, want, have)
+		}
+		for i, cpu := range cpus {
+			if want, have := uint(i), cpu.Processor; want != have {
+				t.Errorf("want %s processor %d, have %d", tt.root, want, have)
+			}
+			if want, have := tt.modelName, cpu.ModelName; want != have {
+				t.Errorf("want %s model name %q, have %q", tt.root, want, have)
+			}
+			if want, have := tt.flags, len(cpu.Flags); want != have {
+				t.Errorf("want %s %d flags, have %d", tt.root, want, have)
+			}
+			if !containsFlag(cpu.Flags, tt.flag) {
+				t.Errorf("want %s flag %s in %v", tt.root, tt.flag, cpu.Flags)
+			}
+		}
+	}
+}
+
+func TestNewCPUInfoProcX86(t *testing.T) {
+	cpus, err := FS("fixtures").NewCPUInfoProc()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cpu := cpus[1]
+	if want, have := "GenuineIntel", cpu.VendorID; want != have {
+		t.Errorf("want vendor id %q, have %q", want, have)
+	}
+	if want, have := 1500.210, cpu.CPUMHz; want != have {
+		t.Errorf("want cpu MHz %f, have %f", want, have)
+	}
+	if want, have := "8192 KB", cpu.CacheSize; want != have {
+		t.Errorf("want cache size %q, have %q", want, have)
+	}
+	if want, have := "1", cpu.CoreID; want != have {
+		t.Errorf("want core id %q, have %q", want, have)
+	}
+	if want, have := "0xb4", cpu.Raw["microcode"]; want != have {
+		t.Errorf("want microcode %q, have %q", want, have)
+	}
+}
+
+func TestNewCPUInfoProcARM(t *testing.T) {
+	cpus, err := FS("fixtures/arm").NewCPUInfoProc()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := "0xd03", cpus[0].Raw["CPU part"]; want != have {
+		t.Errorf("want cpu part %q, have %q", want, have)
+	}
+	// The system wide block following the processors is skipped.
+	if _, ok := cpus[1].Raw["Hardware"]; ok {
+		t.Errorf("want no hardware field for processor 1")
+	}
+}
+
+func containsFlag(flags []string, flag string) bool {
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// diskSectorSize is the unit of the sector counters of /proc/diskstats, which
+// is independent of the sector size of the device.
+const diskSectorSize = 512
+
+// DiskStat is a single line of /proc/diskstats, holding the I/O statistics of
+// one block device or partition. Times are in milliseconds.
+type DiskStat struct {
+	Major         uint32
+	Minor         uint32
+	Name          string
+	ReadIOs       uint64
+	ReadMerges    uint64
+	ReadSectors   uint64
+	ReadTicks     uint64
+	WriteIOs      uint64
+	WriteMerges   uint64
+	WriteSectors  uint64
+	WriteTicks    uint64
+	IOsInProgress uint64
+	// Time the device had I/O in flight.
+	IOTicks uint64
+	// Time the device had I/O in flight, weighted by the number of
+	// requests in flight.
+	TimeInQueue uint64
+	// Discard counters, kernel 4.18+.
+	DiscardIOs     uint64
+	DiscardMerges  uint64
+	DiscardSectors uint64
+	DiscardTicks   uint64
+	// Flush counters, kernel 5.5+.
+	FlushIOs   uint64
+	FlushTicks uint64
+}
+
+// DiskRate holds the iostat-like metrics of a block device between two
+// samples of /proc/diskstats.
+type DiskRate struct {
+	// Completed reads and writes per second.
+	ReadIOPS  float64
+	WriteIOPS float64
+	// Bytes read and written per second.
+	ReadBytesPerSec  float64
+	WriteBytesPerSec float64
+	// Average size of the completed reads and writes in bytes.
+	AvgRequestSize float64
+	// Percentage of time the device had I/O in flight.
+	Utilization float64
+}
+
+// NewDiskStats reads the block device I/O statistics from /proc/diskstats.
+func NewDiskStats() ([]DiskStat, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewDiskStats()
+}
+
+// NewDiskStats reads the block device I/O statistics from the specified
+// `proc` filesystem.
+func (fs FS) NewDiskStats() ([]DiskStat, error) {
+	f, err := os.Open(fs.Path("diskstats"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseDiskStats(f)
+}
+
+// DiskStatsRates computes the rates of all devices present in both samples of
+// /proc/diskstats taken interval apart, keyed by device name. Counters which
+// wrapped at 32 bits, as the time counters do on all platforms, are accounted
+// for. Counters which went backwards otherwise are assumed to have been reset.
+func DiskStatsRates(prev, cur []DiskStat, interval time.Duration) map[string]DiskRate {
+	rates := map[string]DiskRate{}
+	if interval <= 0 {
+		return rates
+	}
+
+	previous := make(map[string]DiskStat, len(prev))
+	for _, p := range prev {
+		previous[p.Name] = p
+	}
+
+	secs := interval.Seconds()
+	for _, c := range cur {
+		p, ok := previous[c.Name]
+		if !ok {
+			continue
+		}
+
+		var (
+			reads        = util.CounterDelta(p.ReadIOs, c.ReadIOs)
+			writes       = util.CounterDelta(p.WriteIOs, c.WriteIOs)
+			readSectors  = util.CounterDelta(p.ReadSectors, c.ReadSectors)
+			writeSectors = util.CounterDelta(p.WriteSectors, c.WriteSectors)
+			ioTicks      = util.CounterDelta(p.IOTicks, c.IOTicks)
+			r            = DiskRate{}
+		)
+		r.ReadIOPS = float64(reads) / secs
+		r.WriteIOPS = float64(writes) / secs
+		r.ReadBytesPerSec = float64(readSectors*diskSectorSize) / secs
+		r.WriteBytesPerSec = float64(writeSectors*diskSectorSize) / secs
+		if ios := reads + writes; ios > 0 {
+			r.AvgRequestSize = float64((readSectors+writeSectors)*diskSectorSize) / float64(ios)
+		}
+		r.Utilization = math.Min(float64(ioTicks)/(secs*1000)*100, 100)
+
+		rates[c.Name] = r
+	}
+
+	return rates
+}
+
+func parseDiskStats(r io.Reader) ([]DiskStat, error) {
+	var (
+		stats   = []DiskStat{}
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 14 && len(fields) != 18 && len(fields) != 20 {
+			return nil, fmt.Errorf("invalid number of fields when parsing diskstats: %q", scanner.Text())
+		}
+
+		major, err := strconv.ParseUint(fields[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (major): %s", fields[0], err)
+		}
+		minor, err := strconv.ParseUint(fields[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (minor): %s", fields[1], err)
+		}
+
+		s := DiskStat{Major: uint32(major), Minor: uint32(minor), Name: fields[2]}
+		if err := util.ParseCounters(s.Name, fields[3:], diskStatCounters(&s)); err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, s)
+	}
+
+	return stats, scanner.Err()
+}
+
+// diskStatCounters returns pointers to the counters of s in the order of the
+// fields following the device name in /proc/diskstats. The stat file of a
+// block device in sysfs holds the same counters.
+func diskStatCounters(s *DiskStat) []*uint64 {
+	return []*uint64{
+		&s.ReadIOs, &s.ReadMerges, &s.ReadSectors, &s.ReadTicks,
+		&s.WriteIOs, &s.WriteMerges, &s.WriteSectors, &s.WriteTicks,
+		&s.IOsInProgress, &s.IOTicks, &s.TimeInQueue,
+		&s.DiscardIOs, &s.DiscardMerges, &s.DiscardSectors, &s.DiscardTicks,
+		&s.FlushIOs, &s.FlushTicks,
+	}
+}
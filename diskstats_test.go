@@ -0,0 +1,81 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewDiskStats(t *testing.T) {
+	stats, err := FS("fixtures/diskutil/prev").NewDiskStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 5, len(stats); want != have {
+		t.Fatalf("want %d devices, have %d", want, have)
+	}
+
+	sda := stats[1]
+	if want, have := "sda", sda.Name; want != have {
+		t.Errorf("want device %q, have %q", want, have)
+	}
+	if want, have := uint64(9600000), sda.ReadSectors; want != have {
+		t.Errorf("want sda read sectors %d, have %d", want, have)
+	}
+	if want, have := uint64(1350000), sda.TimeInQueue; want != have {
+		t.Errorf("want sda time in queue %d, have %d", want, have)
+	}
+	if want, have := uint32(259), stats[4].Major; want != have {
+		t.Errorf("want nvme0n1 major %d, have %d", want, have)
+	}
+}
+
+func TestDiskStatsRates(t *testing.T) {
+	prev, err := FS("fixtures/diskutil/prev").NewDiskStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cur, err := FS("fixtures/diskutil/cur").NewDiskStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rates := DiskStatsRates(prev, cur, 10*time.Second)
+
+	// loop0 disappeared and dm-0 appeared between both samples.
+	for _, name := range []string{"loop0", "dm-0"} {
+		if _, ok := rates[name]; ok {
+			t.Errorf("want no rate for %s", name)
+		}
+	}
+
+	want := DiskRate{
+		ReadIOPS:         200,
+		WriteIOPS:        300,
+		ReadBytesPerSec:  409600 * 512 / 10,
+		WriteBytesPerSec: 614400 * 512 / 10,
+		AvgRequestSize:   (409600 + 614400) * 512 / 5000.0,
+		Utilization:      92,
+	}
+	if have := rates["sda"]; want != have {
+		t.Errorf("want sda rate %+v, have %+v", want, have)
+	}
+
+	// The io_ticks counter of sdb wrapped at 32 bits.
+	if want, have := 100.0, rates["sdb"].Utilization; want != have {
+		t.Errorf("want sdb utilization %f, have %f", want, have)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NewFSCacheStats reads the FS-Cache statistics from /proc/fs/fscache/stats,
+// keyed by group (e.g. "Retrvls") and counter name (e.g. "ok"). Groups
+// spread over multiple lines are merged.
+func NewFSCacheStats() (map[string]map[string]uint64, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewFSCacheStats()
+}
+
+// NewFSCacheStats reads the FS-Cache statistics from the specified `proc`
+// filesystem. It returns an error if the kernel was built without FS-Cache
+// statistics, which can be checked with os.IsNotExist.
+func (fs FS) NewFSCacheStats() (map[string]map[string]uint64, error) {
+	f, err := os.Open(fs.Path("fs/fscache/stats"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseFSCacheStats(f)
+}
+
+func parseFSCacheStats(r io.Reader) (map[string]map[string]uint64, error) {
+	var (
+		stats   = map[string]map[string]uint64{}
+		scanner = bufio.NewScanner(r)
+	)
+
+	// Skip the "FS-Cache statistics" header line.
+	scanner.Scan()
+
+	for scanner.Scan() {
+		// Lines look like "Pages  : mrk=547164 unc=0".
+		parts := strings.SplitN(scanner.Text(), ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("malformed fscache stats line: %q", scanner.Text())
+		}
+
+		group := strings.TrimSpace(parts[0])
+		if stats[group] == nil {
+			stats[group] = map[string]uint64{}
+		}
+		for _, f := range strings.Fields(parts[1]) {
+			kv := strings.SplitN(f, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("malformed fscache counter %q in group %s", f, group)
+			}
+			v, err := strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse %s (%s %s): %s", kv[1], group, kv[0], err)
+			}
+			stats[group][kv[0]] = v
+		}
+	}
+
+	return stats, scanner.Err()
+}
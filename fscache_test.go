@@ -0,0 +1,47 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"os"
+	"testing"
+)
+
+func TestFSCacheStats(t *testing.T) {
+	stats, err := FS("fixtures").NewFSCacheStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		group string
+		name  string
+		want  uint64
+	}{
+		{group: "Retrvls", name: "ok", want: 134201},
+		{group: "Retrvls", name: "nod", want: 8488},
+		{group: "Retrvls", name: "ops", want: 142689},
+		{group: "Cookies", name: "dat", want: 67877},
+		{group: "Pages", name: "mrk", want: 547164},
+		{group: "Ops", name: "ini", want: 689853},
+	} {
+		if have := stats[tt.group][tt.name]; tt.want != have {
+			t.Errorf("want %s %s %d, have %d", tt.group, tt.name, tt.want, have)
+		}
+	}
+
+	if _, err := FS("fixtures/26231").NewFSCacheStats(); !os.IsNotExist(err) {
+		t.Errorf("want not exist error for missing fscache statistics, have %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package util contains helpers shared by the procfs and sysfs packages.
+package util
+
+import "math"
+
+// CounterDelta returns the increase of a counter between two samples. A
+// counter which went backwards either wrapped at 32 bits, as guessed by
+// CounterWrapped, or has been reset and counted up from zero since.
+func CounterDelta(prev, cur uint64) uint64 {
+	if cur >= prev {
+		return cur - prev
+	}
+	if CounterWrapped(prev, cur) {
+		return cur + (math.MaxUint32 - prev) + 1
+	}
+
+	return cur
+}
+
+// CounterWrapped guesses whether a counter which went backwards from prev to
+// cur wrapped at 32 bits rather than being reset: the previous value has to
+// be in the upper and the current one in the lower quarter of the 32-bit
+// range.
+func CounterWrapped(prev, cur uint64) bool {
+	const quarter = (math.MaxUint32 + 1) / 4
+
+	return cur < prev && prev <= math.MaxUint32 && prev >= 3*quarter && cur < quarter
+}
@@ -0,0 +1,35 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCounterDelta(t *testing.T) {
+	for _, tt := range []struct {
+		prev, cur, want uint64
+	}{
+		{prev: 10, cur: 15, want: 5},
+		{prev: 10, cur: 3, want: 3},
+		{prev: math.MaxUint32 - 1, cur: 3, want: 5},
+		{prev: math.MaxUint32 / 2, cur: 3, want: 3},
+		{prev: math.MaxUint64 - 1, cur: 3, want: 3},
+	} {
+		if have := CounterDelta(tt.prev, tt.cur); tt.want != have {
+			t.Errorf("want delta %d from %d to %d, have %d", tt.want, tt.prev, tt.cur, have)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ParseCounters parses fields into the first len(fields) counters, e.g. the
+// counters of a /proc/diskstats line. Counters without a field, as with older
+// kernels, are left untouched. name identifies the line in errors.
+func ParseCounters(name string, fields []string, counters []*uint64) error {
+	if len(fields) > len(counters) {
+		return fmt.Errorf("invalid number of counters for %s: %d", name, len(fields))
+	}
+
+	var err error
+	for i, f := range fields {
+		if *counters[i], err = strconv.ParseUint(f, 10, 64); err != nil {
+			return fmt.Errorf("couldn't parse %s (%s counter %d): %s", f, name, i+1, err)
+		}
+	}
+
+	return nil
+}
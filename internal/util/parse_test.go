@@ -0,0 +1,33 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import "testing"
+
+func TestParseCounters(t *testing.T) {
+	var a, b, c uint64
+	if err := ParseCounters("sda", []string{"1", "2"}, []*uint64{&a, &b, &c}); err != nil {
+		t.Fatal(err)
+	}
+	if a != 1 || b != 2 || c != 0 {
+		t.Errorf("want counters 1 2 0, have %d %d %d", a, b, c)
+	}
+
+	if err := ParseCounters("sda", []string{"1", "2"}, []*uint64{&a}); err == nil {
+		t.Error("want error for too many fields, have none")
+	}
+	if err := ParseCounters("sda", []string{"x"}, []*uint64{&a}); err == nil {
+		t.Error("want error for invalid counter, have none")
+	}
+}
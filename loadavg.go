@@ -0,0 +1,90 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// LoadAvg represents an entry in /proc/loadavg.
+type LoadAvg struct {
+	// Load averages over 1, 5 and 15 minutes.
+	Load1  float64
+	Load5  float64
+	Load15 float64
+	// Number of currently runnable tasks.
+	RunnableTasks int
+	// Number of tasks on the system.
+	TotalTasks int
+	// PID of the most recently created process.
+	LastPID int
+}
+
+// NewLoadAvg returns the load averages read from /proc/loadavg.
+func NewLoadAvg() (LoadAvg, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return LoadAvg{}, err
+	}
+
+	return fs.NewLoadAvg()
+}
+
+// NewLoadAvg returns the load averages read from the specified `proc`
+// filesystem.
+func (fs FS) NewLoadAvg() (LoadAvg, error) {
+	data, err := ioutil.ReadFile(fs.Path("loadavg"))
+	if err != nil {
+		return LoadAvg{}, err
+	}
+
+	return parseLoadAvg(string(data))
+}
+
+func parseLoadAvg(data string) (LoadAvg, error) {
+	// The file looks like "0.50 0.33 0.25 2/1234 5678".
+	fields := strings.Fields(data)
+	if len(fields) != 5 {
+		return LoadAvg{}, fmt.Errorf("unexpected loadavg format: %q", data)
+	}
+
+	var (
+		l   = LoadAvg{}
+		err error
+	)
+	for i, v := range []*float64{&l.Load1, &l.Load5, &l.Load15} {
+		if *v, err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return LoadAvg{}, fmt.Errorf("couldn't parse %s (load): %s", fields[i], err)
+		}
+	}
+
+	tasks := strings.Split(fields[3], "/")
+	if len(tasks) != 2 {
+		return LoadAvg{}, fmt.Errorf("unexpected loadavg tasks format: %q", fields[3])
+	}
+	if l.RunnableTasks, err = strconv.Atoi(tasks[0]); err != nil {
+		return LoadAvg{}, fmt.Errorf("couldn't parse %s (runnable tasks): %s", tasks[0], err)
+	}
+	if l.TotalTasks, err = strconv.Atoi(tasks[1]); err != nil {
+		return LoadAvg{}, fmt.Errorf("couldn't parse %s (total tasks): %s", tasks[1], err)
+	}
+	if l.LastPID, err = strconv.Atoi(fields[4]); err != nil {
+		return LoadAvg{}, fmt.Errorf("couldn't parse %s (last pid): %s", fields[4], err)
+	}
+
+	return l, nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import "testing"
+
+func TestNewLoadAvg(t *testing.T) {
+	l, err := FS("fixtures").NewLoadAvg()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := LoadAvg{
+		Load1:         0.5,
+		Load5:         0.33,
+		Load15:        0.25,
+		RunnableTasks: 2,
+		TotalTasks:    1234,
+		LastPID:       5678,
+	}
+	if want != l {
+		t.Errorf("want load average %+v, have %+v", want, l)
+	}
+}
+
+func TestParseLoadAvgErrors(t *testing.T) {
+	for _, data := range []string{"0.50 0.33 0.25 2 5678", "0.50 0.33 0.25 2/1234", "x 0.33 0.25 2/1234 5678"} {
+		if _, err := parseLoadAvg(data); err == nil {
+			t.Errorf("want error for loadavg %q", data)
+		}
+	}
+}
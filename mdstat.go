@@ -6,11 +6,14 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var (
 	statuslineRE = regexp.MustCompile(`(\d+) blocks .*\[(\d+)/(\d+)\] \[[U_]+\]`)
 	buildlineRE  = regexp.MustCompile(`\((\d+)/\d+\)`)
+	finishRE     = regexp.MustCompile(`finish=(\d+(?:\.\d+)?)min`)
+	speedRE      = regexp.MustCompile(`speed=(\d+)K/sec`)
 )
 
 // MDStat holds info parsed from /proc/mdstat.
@@ -27,6 +30,11 @@ type MDStat struct {
 	BlocksTotal int64
 	// Number of blocks on the device that are in sync.
 	BlocksSynced int64
+	// Current speed of a running resync or recovery in KB/s, 0 otherwise.
+	SyncSpeed int64
+	// Estimated time until a running resync or recovery finishes, 0
+	// otherwise.
+	ETA time.Duration
 }
 
 // ParseMDStat parses an mdstat-file and returns a struct with the relevant infos.
@@ -78,12 +86,20 @@ func (fs FS) ParseMDStat() (mdstates []MDStat, err error) {
 
 		// If device is syncing at the moment, get the number of currently
 		// synced bytes, otherwise that number equals the size of the device.
-		syncedBlocks := size
+		var (
+			syncedBlocks = size
+			speed        int64
+			eta          time.Duration
+		)
 		if strings.Contains(lines[j], "recovery") || strings.Contains(lines[j], "resync") {
 			syncedBlocks, err = evalBuildline(lines[j])
 			if err != nil {
 				return mdStates, fmt.Errorf("error parsing %s: %s", mdStatusFilePath, err)
 			}
+			speed, eta, err = evalBuildProgress(lines[j])
+			if err != nil {
+				return mdStates, fmt.Errorf("error parsing %s: %s", mdStatusFilePath, err)
+			}
 		}
 
 		mdStates = append(mdStates, MDStat{
@@ -93,6 +109,8 @@ func (fs FS) ParseMDStat() (mdstates []MDStat, err error) {
 			DisksTotal:    total,
 			BlocksTotal:   size,
 			BlocksSynced:  syncedBlocks,
+			SyncSpeed:     speed,
+			ETA:           eta,
 		})
 	}
 
@@ -136,3 +154,25 @@ func evalBuildline(buildline string) (syncedBlocks int64, err error) {
 
 	return syncedBlocks, nil
 }
+
+// evalBuildProgress returns the speed in KB/s and the estimated time left of
+// a resync or recovery. Either is 0 if missing from the buildline, e.g. for a
+// delayed resync.
+func evalBuildProgress(buildline string) (speed int64, eta time.Duration, err error) {
+	if matches := speedRE.FindStringSubmatch(buildline); matches != nil {
+		speed, err = strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%s in buildline: %s", err, buildline)
+		}
+	}
+
+	if matches := finishRE.FindStringSubmatch(buildline); matches != nil {
+		minutes, err := strconv.ParseFloat(matches[1], 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%s in buildline: %s", err, buildline)
+		}
+		eta = time.Duration(minutes * float64(time.Minute))
+	}
+
+	return speed, eta, nil
+}
@@ -2,6 +2,7 @@ package procfs
 
 import (
 	"testing"
+	"time"
 )
 
 func TestMDStat(t *testing.T) {
@@ -11,13 +12,13 @@ func TestMDStat(t *testing.T) {
 	}
 
 	refs := map[string]MDStat{
-		"md3":   {"md3", "active", 8, 8, 5853468288, 5853468288},
-		"md127": {"md127", "active", 2, 2, 312319552, 312319552},
-		"md0":   {"md0", "active", 2, 2, 248896, 248896},
-		"md4":   {"md4", "inactive", 2, 2, 4883648, 4883648},
-		"md6":   {"md6", "active", 1, 2, 195310144, 16775552},
-		"md8":   {"md8", "active", 2, 2, 195310144, 16775552},
-		"md7":   {"md7", "active", 3, 4, 7813735424, 7813735424},
+		"md3":   {"md3", "active", 8, 8, 5853468288, 5853468288, 0, 0},
+		"md127": {"md127", "active", 2, 2, 312319552, 312319552, 0, 0},
+		"md0":   {"md0", "active", 2, 2, 248896, 248896, 0, 0},
+		"md4":   {"md4", "inactive", 2, 2, 4883648, 4883648, 0, 0},
+		"md6":   {"md6", "active", 1, 2, 195310144, 16775552, 259783, 17 * time.Minute},
+		"md8":   {"md8", "active", 2, 2, 195310144, 16775552, 120000, 4*time.Minute + 30*time.Second},
+		"md7":   {"md7", "active", 3, 4, 7813735424, 7813735424, 0, 0},
 	}
 
 	if want, have := len(refs), len(mdStates); want != have {
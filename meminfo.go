@@ -0,0 +1,219 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Meminfo represents memory statistics read from /proc/meminfo. Values given
+// in kB by the kernel are converted to bytes, page counts are kept as is.
+type Meminfo struct {
+	// Total usable RAM in bytes.
+	MemTotal uint64
+	// Free RAM in bytes.
+	MemFree uint64
+	// Estimate of the memory available for starting new applications
+	// without swapping, in bytes. Kernel 3.14+.
+	MemAvailable uint64
+	// Memory in buffer cache in bytes.
+	Buffers uint64
+	// Memory in the page cache (excluding swap cache) in bytes.
+	Cached uint64
+	// Memory swapped out and back in, but still in the swap file, in bytes.
+	SwapCached uint64
+	// Memory used recently, which is usually not reclaimed, in bytes.
+	Active uint64
+	// Memory used less recently, which is eligible for reclaim, in bytes.
+	Inactive uint64
+	// Total swap space in bytes.
+	SwapTotal uint64
+	// Unused swap space in bytes.
+	SwapFree uint64
+	// Memory waiting to be written back to disk in bytes.
+	Dirty uint64
+	// Memory actively being written back to disk in bytes.
+	Writeback uint64
+	// Memory in anonymous, i.e. not file-backed, pages in bytes.
+	AnonPages uint64
+	// Memory of mapped files in bytes.
+	Mapped uint64
+	// Memory used by shared memory and tmpfs in bytes.
+	Shmem uint64
+	// Memory used by in-kernel data structure caches in bytes.
+	Slab uint64
+	// Part of the slab which might be reclaimed, such as caches, in bytes.
+	SlabReclaimable uint64
+	// Part of the slab which cannot be reclaimed under memory pressure, in
+	// bytes.
+	SlabUnreclaim uint64
+	// Memory which can be allocated before the overcommit limit is reached,
+	// in bytes. Only enforced with vm.overcommit_memory=2.
+	CommitLimit uint64
+	// Memory promised to processes, whether used or not, in bytes.
+	CommittedAS uint64
+	// Size of the pool of huge pages, in pages.
+	HugePagesTotal uint64
+	// Number of huge pages in the pool that are not yet allocated.
+	HugePagesFree uint64
+	// Size of a huge page in bytes.
+	Hugepagesize uint64
+	// Kernel memory mapped with 4k pages in bytes. The DirectMap fields are
+	// only reported on x86, and are zero elsewhere.
+	DirectMap4k uint64
+	// Kernel memory mapped with 2M pages in bytes.
+	DirectMap2M uint64
+	// Kernel memory mapped with 1G pages in bytes.
+	DirectMap1G uint64
+	// All lines without a field above, keyed by their name as printed by
+	// the kernel, e.g. "Active(anon)". Values in kB are converted to bytes
+	// as well.
+	Other map[string]uint64
+}
+
+// NewMeminfo returns the memory statistics read from /proc/meminfo.
+func NewMeminfo() (Meminfo, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return Meminfo{}, err
+	}
+
+	return fs.NewMeminfo()
+}
+
+// NewMeminfo returns the memory statistics read from the specified `proc`
+// filesystem.
+func (fs FS) NewMeminfo() (Meminfo, error) {
+	f, err := os.Open(fs.Path("meminfo"))
+	if err != nil {
+		return Meminfo{}, err
+	}
+	defer f.Close()
+
+	return parseMeminfo(f)
+}
+
+// MemUsed returns the memory in use in bytes, which is the total memory minus
+// the free memory, buffers and page cache. It is 0 if the fields, which aren't
+// read atomically, add up to more than the total memory.
+func (m Meminfo) MemUsed() uint64 {
+	free := m.MemFree + m.Buffers + m.Cached
+	if free > m.MemTotal {
+		return 0
+	}
+
+	return m.MemTotal - free
+}
+
+// CommitPressure returns the committed memory as a ratio of the commit limit.
+// Values above 1 mean more memory was promised to processes than the limit
+// allows, which risks OOM kills should processes actually use it.
+func (m Meminfo) CommitPressure() float64 {
+	if m.CommitLimit == 0 {
+		return 0
+	}
+
+	return float64(m.CommittedAS) / float64(m.CommitLimit)
+}
+
+func parseMeminfo(r io.Reader) (Meminfo, error) {
+	var (
+		m       = Meminfo{Other: map[string]uint64{}}
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		// Lines look like "MemTotal:       16310580 kB" or
+		// "HugePages_Total:       0".
+		parts := strings.Fields(scanner.Text())
+		if len(parts) < 2 || len(parts) > 3 {
+			return Meminfo{}, fmt.Errorf("invalid number of fields when parsing meminfo: %q", scanner.Text())
+		}
+
+		v, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return Meminfo{}, fmt.Errorf("couldn't parse %s (%s): %s", parts[1], parts[0], err)
+		}
+		if len(parts) == 3 {
+			if parts[2] != "kB" {
+				return Meminfo{}, fmt.Errorf("unexpected unit %s in meminfo line: %q", parts[2], scanner.Text())
+			}
+			v *= 1024
+		}
+
+		switch key := strings.TrimSuffix(parts[0], ":"); key {
+		case "MemTotal":
+			m.MemTotal = v
+		case "MemFree":
+			m.MemFree = v
+		case "MemAvailable":
+			m.MemAvailable = v
+		case "Buffers":
+			m.Buffers = v
+		case "Cached":
+			m.Cached = v
+		case "SwapCached":
+			m.SwapCached = v
+		case "Active":
+			m.Active = v
+		case "Inactive":
+			m.Inactive = v
+		case "SwapTotal":
+			m.SwapTotal = v
+		case "SwapFree":
+			m.SwapFree = v
+		case "Dirty":
+			m.Dirty = v
+		case "Writeback":
+			m.Writeback = v
+		case "AnonPages":
+			m.AnonPages = v
+		case "Mapped":
+			m.Mapped = v
+		case "Shmem":
+			m.Shmem = v
+		case "Slab":
+			m.Slab = v
+		case "SReclaimable":
+			m.SlabReclaimable = v
+		case "SUnreclaim":
+			m.SlabUnreclaim = v
+		case "CommitLimit":
+			m.CommitLimit = v
+		case "Committed_AS":
+			m.CommittedAS = v
+		case "HugePages_Total":
+			m.HugePagesTotal = v
+		case "HugePages_Free":
+			m.HugePagesFree = v
+		case "Hugepagesize":
+			m.Hugepagesize = v
+		case "DirectMap4k":
+			m.DirectMap4k = v
+		case "DirectMap2M":
+			m.DirectMap2M = v
+		case "DirectMap1G":
+			m.DirectMap1G = v
+		default:
+			m.Other[key] = v
+		}
+	}
+
+	return m, scanner.Err()
+}
@@ -0,0 +1,80 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import "testing"
+
+func TestMeminfo(t *testing.T) {
+	m, err := FS("fixtures").NewMeminfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		want uint64
+		have uint64
+	}{
+		{name: "MemTotal", want: 15666184 * 1024, have: m.MemTotal},
+		{name: "MemAvailable", want: 7560860 * 1024, have: m.MemAvailable},
+		{name: "Active", want: 6761276 * 1024, have: m.Active},
+		{name: "Dirty", want: 768 * 1024, have: m.Dirty},
+		{name: "Slab", want: 1807264 * 1024, have: m.Slab},
+		{name: "Active(anon)", want: 3188204 * 1024, have: m.Other["Active(anon)"]},
+		{name: "HugePages_Rsvd", want: 0, have: m.Other["HugePages_Rsvd"]},
+		{name: "SlabReclaimable", want: 1738124 * 1024, have: m.SlabReclaimable},
+		{name: "SlabUnreclaim", want: 69140 * 1024, have: m.SlabUnreclaim},
+		{name: "CommitLimit", want: 7833092 * 1024, have: m.CommitLimit},
+		{name: "CommittedAS", want: 530844 * 1024, have: m.CommittedAS},
+		{name: "HugePagesTotal", want: 1024, have: m.HugePagesTotal},
+		{name: "HugePagesFree", want: 512, have: m.HugePagesFree},
+		{name: "Hugepagesize", want: 2048 * 1024, have: m.Hugepagesize},
+		{name: "DirectMap4k", want: 387524 * 1024, have: m.DirectMap4k},
+		{name: "DirectMap2M", want: 14141440 * 1024, have: m.DirectMap2M},
+		{name: "DirectMap1G", want: 2097152 * 1024, have: m.DirectMap1G},
+		{name: "MemUsed", want: (15666184 - 440324 - 1020128 - 6114092) * 1024, have: m.MemUsed()},
+	} {
+		if test.want != test.have {
+			t.Errorf("want %s %d, have %d", test.name, test.want, test.have)
+		}
+	}
+
+	if _, ok := m.Other["MemTotal"]; ok {
+		t.Error("want MemTotal only as typed field")
+	}
+	if _, ok := m.Other["HugePages_Rsvd"]; !ok {
+		t.Error("want HugePages_Rsvd in other lines")
+	}
+
+	// The fields can add up to more than MemTotal, as they aren't read atomically.
+	m = Meminfo{MemTotal: 1024, MemFree: 512, Buffers: 256, Cached: 512}
+	if want, have := uint64(0), m.MemUsed(); want != have {
+		t.Errorf("want MemUsed %d, have %d", want, have)
+	}
+}
+
+func TestMeminfoCommitPressure(t *testing.T) {
+	m, err := FS("fixtures/overcommit").NewMeminfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pressure := m.CommitPressure()
+	if want, have := 5932168.0/2015424, pressure; want != have {
+		t.Errorf("want commit pressure %f, have %f", want, have)
+	}
+	if pressure <= 1 {
+		t.Errorf("want commit pressure above 1 for overcommitted system, have %f", pressure)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// MountEntry is a single line of /proc/mounts, describing a mounted
+// filesystem in fstab format.
+type MountEntry struct {
+	// Name of the mounted device or remote filesystem.
+	Device string
+	// Path the filesystem is mounted on.
+	MountPoint string
+	// Type of the filesystem, e.g. "ext4".
+	FSType string
+	// Mount options, e.g. "rw" or "relatime".
+	Options []string
+	// Whether the filesystem needs to be dumped, always 0 in /proc/mounts.
+	Dump int
+	// Order of filesystem checks at boot, always 0 in /proc/mounts.
+	Pass int
+}
+
+// NewMounts reads the mounted filesystems of the current mount namespace from
+// /proc/mounts.
+func NewMounts() ([]MountEntry, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewMounts()
+}
+
+// NewMounts reads the mounted filesystems from the specified `proc`
+// filesystem.
+func (fs FS) NewMounts() ([]MountEntry, error) {
+	f, err := os.Open(fs.Path("mounts"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseMounts(f)
+}
+
+func parseMounts(r io.Reader) ([]MountEntry, error) {
+	var (
+		mounts  = []MountEntry{}
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("invalid number of fields when parsing mounts: %q", scanner.Text())
+		}
+
+		var (
+			m = MountEntry{
+				Device:     unescapeMountField(fields[0]),
+				MountPoint: unescapeMountField(fields[1]),
+				FSType:     unescapeMountField(fields[2]),
+				Options:    strings.Split(fields[3], ","),
+			}
+			err error
+		)
+		if m.Dump, err = strconv.Atoi(fields[4]); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (dump): %s", fields[4], err)
+		}
+		if m.Pass, err = strconv.Atoi(fields[5]); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (pass): %s", fields[5], err)
+		}
+
+		mounts = append(mounts, m)
+	}
+
+	return mounts, scanner.Err()
+}
+
+// unescapeMountField replaces the octal escapes the kernel uses for space,
+// tab, newline and backslash in mount fields, e.g. "\040", by the character.
+func unescapeMountField(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if c, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,59 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewMounts(t *testing.T) {
+	mounts, err := FS("fixtures").NewMounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 5, len(mounts); want != have {
+		t.Fatalf("want %d mounts, have %d", want, have)
+	}
+
+	want := MountEntry{
+		Device:     "/dev/sdb1",
+		MountPoint: "/mnt/backup disk",
+		FSType:     "ext4",
+		Options:    []string{"rw", "noatime"},
+		Dump:       0,
+		Pass:       2,
+	}
+	if have := mounts[3]; !reflect.DeepEqual(want, have) {
+		t.Errorf("want mount %+v, have %+v", want, have)
+	}
+}
+
+func TestUnescapeMountField(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want string
+	}{
+		{in: "/mnt/plain", want: "/mnt/plain"},
+		{in: `/mnt/a\040b`, want: "/mnt/a b"},
+		{in: `/mnt/a\011b`, want: "/mnt/a\tb"},
+		{in: `/mnt/a\134b`, want: `/mnt/a\b`},
+		{in: `/mnt/trailing\04`, want: `/mnt/trailing\04`},
+	} {
+		if have := unescapeMountField(tt.in); tt.want != have {
+			t.Errorf("want %q, have %q", tt.want, have)
+		}
+	}
+}
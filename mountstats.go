@@ -211,6 +211,38 @@ type NFSTransportStats struct {
 	CumulativePendingQueue uint64
 }
 
+// NewMountStats reads the mounts of the current process from
+// /proc/self/mountstats, including detailed statistics for NFS mounts.
+func NewMountStats() ([]*Mount, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewMountStats()
+}
+
+// NewMountStats reads the mounts of the current process from the specified
+// `proc` filesystem.
+func (fs FS) NewMountStats() ([]*Mount, error) {
+	p, err := fs.Self()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.MountStats()
+}
+
+// AverageRTT returns the average time it took to get a reply back after a
+// request of the operation was transmitted, 0 if there were no requests.
+func (o NFSOperationStats) AverageRTT() time.Duration {
+	if o.Requests == 0 {
+		return 0
+	}
+
+	return o.CumulativeTotalResponseTime / time.Duration(o.Requests)
+}
+
 // parseMountStats parses a /proc/[pid]/mountstats file and returns a slice
 // of Mount structures containing detailed information about each mount.
 // If available, statistics for each mount are parsed as well.
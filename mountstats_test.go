@@ -271,3 +271,37 @@ func mountsStr(mounts []*Mount) string {
 
 	return out
 }
+
+func TestNewMountStats(t *testing.T) {
+	mounts, err := FS("fixtures").NewMountStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 5, len(mounts); want != have {
+		t.Fatalf("want %d mounts, have %d", want, have)
+	}
+	// Mounts other than NFS carry no statistics.
+	if mounts[0].Stats != nil {
+		t.Errorf("want no stats for %s, have %+v", mounts[0].Type, mounts[0].Stats)
+	}
+
+	stats, ok := mounts[4].Stats.(*MountStatsNFS)
+	if !ok {
+		t.Fatalf("want nfs stats for %s, have %T", mounts[4].Mount, mounts[4].Stats)
+	}
+	if want, have := uint64(1207640230), stats.Bytes.Read; want != have {
+		t.Errorf("want bytes read %d, have %d", want, have)
+	}
+
+	read := stats.Operations[1]
+	if want, have := "READ", read.Operation; want != have {
+		t.Fatalf("want operation %s, have %s", want, have)
+	}
+	if want, have := 79386*time.Millisecond/1298, read.AverageRTT(); want != have {
+		t.Errorf("want READ average rtt %s, have %s", want, have)
+	}
+	if want, have := time.Duration(0), stats.Operations[2].AverageRTT(); want != have {
+		t.Errorf("want WRITE average rtt %s, have %s", want, have)
+	}
+}
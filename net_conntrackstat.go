@@ -0,0 +1,153 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ConntrackStatEntry holds the conntrack statistics of a single CPU, as
+// exposed in /proc/net/stat/nf_conntrack.
+type ConntrackStatEntry struct {
+	// Number of entries in the conntrack table.
+	Entries uint64
+	// Number of successful lookups.
+	Found uint64
+	// Number of packets which could not be tracked.
+	Invalid uint64
+	// Number of packets which were already tracked or not trackable.
+	Ignore uint64
+	// Number of entries inserted into the table.
+	Insert uint64
+	// Number of entries which could not be inserted.
+	InsertFailed uint64
+	// Number of packets dropped because conntrack failed.
+	Drop uint64
+	// Number of entries dropped to make room when the table was full.
+	EarlyDrop uint64
+	// Number of lookups restarted because of a hash resize.
+	SearchRestart uint64
+}
+
+// NewConntrackStat reads the per-CPU conntrack statistics.
+func NewConntrackStat() ([]ConntrackStatEntry, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewConntrackStat()
+}
+
+// NewConntrackStat reads the per-CPU conntrack statistics from the specified
+// `proc` filesystem.
+func (fs FS) NewConntrackStat() ([]ConntrackStatEntry, error) {
+	f, err := os.Open(fs.Path("net/stat/nf_conntrack"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseConntrackStat(f)
+}
+
+// ConntrackInsertFailureRatio returns the ratio of failed to attempted
+// conntrack table insertions summed up over all CPUs.
+func ConntrackInsertFailureRatio() (float64, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return 0, err
+	}
+
+	return fs.ConntrackInsertFailureRatio()
+}
+
+// ConntrackInsertFailureRatio returns the ratio of failed to attempted
+// conntrack table insertions summed up over all CPUs. A rising ratio is a
+// leading indicator of conntrack table exhaustion. If no insertion happened
+// yet, the ratio is 0.
+func (fs FS) ConntrackInsertFailureRatio() (float64, error) {
+	entries, err := fs.NewConntrackStat()
+	if err != nil {
+		return 0, err
+	}
+
+	var insert, failed uint64
+	for _, e := range entries {
+		insert += e.Insert
+		failed += e.InsertFailed
+	}
+	if insert == 0 {
+		return 0, nil
+	}
+
+	return float64(failed) / float64(insert), nil
+}
+
+func parseConntrackStat(r io.Reader) ([]ConntrackStatEntry, error) {
+	var (
+		entries = []ConntrackStatEntry{}
+		scanner = bufio.NewScanner(r)
+	)
+
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("conntrack stat corrupt: missing header")
+	}
+	header := strings.Fields(scanner.Text())
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != len(header) {
+			return nil, fmt.Errorf("mismatch in number of conntrack stat fields, header %d, line %d", len(header), len(fields))
+		}
+
+		e := ConntrackStatEntry{}
+		for i, name := range header {
+			v, err := strconv.ParseUint(fields[i], 16, 64)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse %s (%s): %s", fields[i], name, err)
+			}
+
+			switch name {
+			case "entries":
+				e.Entries = v
+			case "found":
+				e.Found = v
+			case "invalid":
+				e.Invalid = v
+			case "ignore":
+				e.Ignore = v
+			case "insert":
+				e.Insert = v
+			case "insert_failed":
+				e.InsertFailed = v
+			case "drop":
+				e.Drop = v
+			case "early_drop":
+				e.EarlyDrop = v
+			case "search_restart":
+				e.SearchRestart = v
+			}
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, scanner.Err()
+}
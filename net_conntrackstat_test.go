@@ -0,0 +1,54 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import "testing"
+
+func TestConntrackStat(t *testing.T) {
+	entries, err := FS("fixtures").NewConntrackStat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 2, len(entries); want != have {
+		t.Fatalf("want %d per-CPU entries, have %d", want, have)
+	}
+
+	for _, test := range []struct {
+		name string
+		want uint64
+		have uint64
+	}{
+		{name: "cpu0 entries", want: 33, have: entries[0].Entries},
+		{name: "cpu0 ignore", want: 22666, have: entries[0].Ignore},
+		{name: "cpu0 insert", want: 400, have: entries[0].Insert},
+		{name: "cpu1 insert_failed", want: 15, have: entries[1].InsertFailed},
+		{name: "cpu1 search_restart", want: 2, have: entries[1].SearchRestart},
+	} {
+		if test.want != test.have {
+			t.Errorf("want %s %d, have %d", test.name, test.want, test.have)
+		}
+	}
+}
+
+func TestConntrackInsertFailureRatio(t *testing.T) {
+	ratio, err := FS("fixtures").ConntrackInsertFailureRatio()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 0.02, ratio; want != have {
+		t.Errorf("want insert failure ratio %v, have %v", want, have)
+	}
+}
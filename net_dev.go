@@ -0,0 +1,181 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// NetDevLine is a single line parsed from /proc/net/dev, holding the counters
+// of one network interface.
+type NetDevLine struct {
+	Name         string
+	RxBytes      uint64
+	RxPackets    uint64
+	RxErrors     uint64
+	RxDropped    uint64
+	RxFIFO       uint64
+	RxFrame      uint64
+	RxCompressed uint64
+	RxMulticast  uint64
+	TxBytes      uint64
+	TxPackets    uint64
+	TxErrors     uint64
+	TxDropped    uint64
+	TxFIFO       uint64
+	TxCollisions uint64
+	TxCarrier    uint64
+	TxCompressed uint64
+}
+
+// NewNetDev reads the network interface counters from /proc/net/dev, keyed by
+// interface name.
+func NewNetDev() (map[string]NetDevLine, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetDev()
+}
+
+// NewNetDev reads the network interface counters from the specified `proc`
+// filesystem.
+func (fs FS) NewNetDev() (map[string]NetDevLine, error) {
+	f, err := os.Open(fs.Path("net/dev"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseNetDev(f)
+}
+
+// NetDevCounterReset returns true if any counter of cur is lower than in prev,
+// either because the driver reset its counters or because a 32-bit counter
+// wrapped. Rates computed over such an interval are bogus, use
+// NetDevCounterWrapped to tell both cases apart.
+func NetDevCounterReset(prev, cur NetDevLine) bool {
+	p, c := prev.counters(), cur.counters()
+	for i := range p {
+		if c[i] < p[i] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NetDevCounterResets applies NetDevCounterReset to all interfaces present in
+// both samples.
+func NetDevCounterResets(prev, cur map[string]NetDevLine) map[string]bool {
+	resets := map[string]bool{}
+	for name, c := range cur {
+		if p, ok := prev[name]; ok {
+			resets[name] = NetDevCounterReset(p, c)
+		}
+	}
+
+	return resets
+}
+
+// NetDevCounterWrapped guesses whether all counters which went backwards
+// between prev and cur did so because they wrapped at 32 bits rather than
+// being reset: the previous value has to be in the upper and the current one
+// in the lower quarter of the 32-bit range. It returns false if no counter
+// went backwards.
+func NetDevCounterWrapped(prev, cur NetDevLine) bool {
+	var (
+		p, c    = prev.counters(), cur.counters()
+		wrapped = false
+	)
+	for i := range p {
+		if c[i] >= p[i] {
+			continue
+		}
+		if !util.CounterWrapped(p[i], c[i]) {
+			return false
+		}
+		wrapped = true
+	}
+
+	return wrapped
+}
+
+func (l NetDevLine) counters() [16]uint64 {
+	return [16]uint64{
+		l.RxBytes, l.RxPackets, l.RxErrors, l.RxDropped,
+		l.RxFIFO, l.RxFrame, l.RxCompressed, l.RxMulticast,
+		l.TxBytes, l.TxPackets, l.TxErrors, l.TxDropped,
+		l.TxFIFO, l.TxCollisions, l.TxCarrier, l.TxCompressed,
+	}
+}
+
+func parseNetDev(r io.Reader) (map[string]NetDevLine, error) {
+	var (
+		netDev  = map[string]NetDevLine{}
+		scanner = bufio.NewScanner(r)
+	)
+
+	// Skip the two header lines.
+	scanner.Scan()
+	scanner.Scan()
+
+	for scanner.Scan() {
+		line, err := parseNetDevLine(scanner.Text())
+		if err != nil {
+			return nil, err
+		}
+		netDev[line.Name] = line
+	}
+
+	return netDev, scanner.Err()
+}
+
+func parseNetDevLine(rawLine string) (NetDevLine, error) {
+	// The counters may directly follow the colon, e.g. "lo:15223654".
+	parts := strings.SplitN(rawLine, ":", 2)
+	if len(parts) != 2 {
+		return NetDevLine{}, fmt.Errorf("invalid net/dev line, missing colon: %q", rawLine)
+	}
+	fields := strings.Fields(parts[1])
+	if len(fields) != 16 {
+		return NetDevLine{}, fmt.Errorf("invalid number of fields when parsing net/dev: %q", rawLine)
+	}
+
+	var (
+		line = NetDevLine{Name: strings.TrimSpace(parts[0])}
+		dsts = []*uint64{
+			&line.RxBytes, &line.RxPackets, &line.RxErrors, &line.RxDropped,
+			&line.RxFIFO, &line.RxFrame, &line.RxCompressed, &line.RxMulticast,
+			&line.TxBytes, &line.TxPackets, &line.TxErrors, &line.TxDropped,
+			&line.TxFIFO, &line.TxCollisions, &line.TxCarrier, &line.TxCompressed,
+		}
+		err error
+	)
+	for i, f := range fields {
+		if *dsts[i], err = strconv.ParseUint(f, 10, 64); err != nil {
+			return NetDevLine{}, fmt.Errorf("couldn't parse %s (column %d of %s): %s", f, i+1, line.Name, err)
+		}
+	}
+
+	return line, nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewNetDevSNMP6 reads the per-interface IPv6 statistics from
+// /proc/net/dev_snmp6, keyed by interface name and counter name.
+func NewNetDevSNMP6() (map[string]map[string]int64, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetDevSNMP6()
+}
+
+// NewNetDevSNMP6 reads the per-interface IPv6 statistics from the specified
+// `proc` filesystem.
+func (fs FS) NewNetDevSNMP6() (map[string]map[string]int64, error) {
+	dir := fs.Path("net/dev_snmp6")
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]map[string]int64, len(files))
+	for _, f := range files {
+		s, err := parseNetDevSNMP6File(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		stats[f.Name()] = s
+	}
+
+	return stats, nil
+}
+
+// NetDevSNMP6ErrorRate returns the rate of received ICMPv6 errors per second
+// for each interface between two samples of /proc/net/dev_snmp6 taken
+// interval apart. Counters missing from a sample are treated as zero.
+func NetDevSNMP6ErrorRate(prev, cur map[string]map[string]int64, interval time.Duration) map[string]float64 {
+	rates := map[string]float64{}
+	if interval <= 0 {
+		return rates
+	}
+
+	for iface, counters := range cur {
+		delta := counters["Icmp6InErrors"] - prev[iface]["Icmp6InErrors"]
+		if delta < 0 {
+			// The counters were reset, e.g. because the interface was
+			// recreated.
+			delta = counters["Icmp6InErrors"]
+		}
+		rates[iface] = float64(delta) / interval.Seconds()
+	}
+
+	return rates
+}
+
+func parseNetDevSNMP6File(file string) (map[string]int64, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseNetDevSNMP6(f)
+}
+
+func parseNetDevSNMP6(r io.Reader) (map[string]int64, error) {
+	var (
+		stats   = map[string]int64{}
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid number of fields when parsing dev_snmp6: %q", scanner.Text())
+		}
+
+		v, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (%s): %s", fields[1], fields[0], err)
+		}
+		stats[fields[0]] = v
+	}
+
+	return stats, scanner.Err()
+}
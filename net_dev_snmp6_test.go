@@ -0,0 +1,63 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNetDevSNMP6(t *testing.T) {
+	stats, err := FS("fixtures").NewNetDevSNMP6()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 2, len(stats); want != have {
+		t.Fatalf("want %d interfaces, have %d", want, have)
+	}
+	if want, have := int64(3), stats["eth0"]["Icmp6InErrors"]; want != have {
+		t.Errorf("want eth0 Icmp6InErrors %d, have %d", want, have)
+	}
+	if want, have := int64(1), stats["lo"]["ifIndex"]; want != have {
+		t.Errorf("want lo ifIndex %d, have %d", want, have)
+	}
+}
+
+func TestNetDevSNMP6ErrorRate(t *testing.T) {
+	prev := map[string]map[string]int64{
+		"eth0": {"Icmp6InErrors": 3},
+		"eth1": {"Icmp6InMsgs": 10},
+	}
+	cur := map[string]map[string]int64{
+		"eth0": {"Icmp6InErrors": 33},
+		"eth1": {"Icmp6InMsgs": 20, "Icmp6InErrors": 5},
+		"lo":   {},
+	}
+
+	rates := NetDevSNMP6ErrorRate(prev, cur, 10*time.Second)
+
+	for _, test := range []struct {
+		iface string
+		want  float64
+	}{
+		{iface: "eth0", want: 3},
+		{iface: "eth1", want: 0.5},
+		{iface: "lo", want: 0},
+	} {
+		if have := rates[test.iface]; test.want != have {
+			t.Errorf("want %s error rate %v, have %v", test.iface, test.want, have)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNetDevCounterReset(t *testing.T) {
+	prev := NetDevLine{Name: "eth0", RxBytes: 4294000000, TxBytes: 1000}
+
+	for _, tt := range []struct {
+		name    string
+		cur     NetDevLine
+		reset   bool
+		wrapped bool
+	}{
+		{name: "increase", cur: NetDevLine{Name: "eth0", RxBytes: 4294100000, TxBytes: 2000}},
+		{name: "wrap", cur: NetDevLine{Name: "eth0", RxBytes: 1000, TxBytes: 2000}, reset: true, wrapped: true},
+		{name: "reset", cur: NetDevLine{Name: "eth0", RxBytes: 1000, TxBytes: 100}, reset: true},
+	} {
+		if want, have := tt.reset, NetDevCounterReset(prev, tt.cur); want != have {
+			t.Errorf("want %s counter reset %t, have %t", tt.name, want, have)
+		}
+		if want, have := tt.wrapped, NetDevCounterWrapped(prev, tt.cur); want != have {
+			t.Errorf("want %s counter wrapped %t, have %t", tt.name, want, have)
+		}
+	}
+}
+
+func TestNetDevCounterResets(t *testing.T) {
+	prev := map[string]NetDevLine{
+		"eth0": {RxPackets: 10},
+		"eth1": {RxPackets: 10},
+	}
+	cur := map[string]NetDevLine{
+		"eth0": {RxPackets: 20},
+		"eth1": {RxPackets: 5},
+		"eth2": {RxPackets: 1},
+	}
+
+	want := map[string]bool{"eth0": false, "eth1": true}
+	if have := NetDevCounterResets(prev, cur); !reflect.DeepEqual(want, have) {
+		t.Errorf("want counter resets %v, have %v", want, have)
+	}
+}
+
+func TestNetDevParseLine(t *testing.T) {
+	netDev, err := FS("fixtures").NewNetDev()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 3, len(netDev); want != have {
+		t.Fatalf("want %d interfaces, have %d", want, have)
+	}
+	if want, have := uint64(15223654), netDev["lo"].RxBytes; want != have {
+		t.Errorf("want lo rx bytes %d, have %d", want, have)
+	}
+	if want, have := uint64(11), netDev["wlan0"].RxDropped; want != have {
+		t.Errorf("want wlan0 rx dropped %d, have %d", want, have)
+	}
+	for _, tt := range []struct {
+		name string
+		want uint64
+		have uint64
+	}{
+		{name: "rx fifo", want: 1, have: netDev["wlan0"].RxFIFO},
+		{name: "rx frame", want: 2, have: netDev["wlan0"].RxFrame},
+		{name: "rx compressed", want: 3, have: netDev["wlan0"].RxCompressed},
+		{name: "rx multicast", want: 1842, have: netDev["wlan0"].RxMulticast},
+		{name: "tx fifo", want: 4, have: netDev["wlan0"].TxFIFO},
+		{name: "tx collisions", want: 5, have: netDev["wlan0"].TxCollisions},
+		{name: "tx carrier", want: 6, have: netDev["wlan0"].TxCarrier},
+		{name: "tx compressed", want: 7, have: netDev["wlan0"].TxCompressed},
+	} {
+		if tt.want != tt.have {
+			t.Errorf("want wlan0 %s %d, have %d", tt.name, tt.want, tt.have)
+		}
+	}
+
+	want := NetDevLine{
+		Name:      "eth0",
+		RxBytes:   874354587,
+		RxPackets: 1036395,
+		TxBytes:   563352563,
+		TxPackets: 732147,
+	}
+	if have := netDev["eth0"]; want != have {
+		t.Errorf("want eth0 %+v, have %+v", want, have)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NetICMP holds the ICMP statistics of the system, with the message counters
+// broken out by ICMP type.
+type NetICMP struct {
+	// Aggregate counters, e.g. "InMsgs" or "OutDestUnreachs".
+	Stats map[string]int64
+	// Number of received messages by ICMP type, e.g. 3 for destination
+	// unreachable. Only types seen at least once are present.
+	InType map[int]int64
+	// Number of sent messages by ICMP type.
+	OutType map[int]int64
+}
+
+// NewNetICMP reads the ICMP statistics from /proc/net/snmp.
+func NewNetICMP() (NetICMP, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return NetICMP{}, err
+	}
+
+	return fs.NewNetICMP()
+}
+
+// NewNetICMP reads the ICMP statistics from the specified `proc` filesystem.
+// The per-type counters are taken from the IcmpMsg lines, whose columns vary
+// with the types seen so far.
+func (fs FS) NewNetICMP() (NetICMP, error) {
+	f, err := os.Open(fs.Path("net/snmp"))
+	if err != nil {
+		return NetICMP{}, err
+	}
+	defer f.Close()
+
+	snmp, err := parseNetProtoStats(f)
+	if err != nil {
+		return NetICMP{}, err
+	}
+
+	icmp := NetICMP{Stats: snmp["Icmp"]}
+	if icmp.Stats == nil {
+		return NetICMP{}, fmt.Errorf("Icmp section not found in snmp")
+	}
+	if icmp.InType, icmp.OutType, err = parseICMPTypes(snmp["IcmpMsg"], ""); err != nil {
+		return NetICMP{}, err
+	}
+
+	return icmp, nil
+}
+
+// NewNetICMP6 reads the ICMPv6 statistics from /proc/net/snmp6.
+func NewNetICMP6() (NetICMP, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return NetICMP{}, err
+	}
+
+	return fs.NewNetICMP6()
+}
+
+// NewNetICMP6 reads the ICMPv6 statistics from the specified `proc`
+// filesystem. The "Icmp6" prefix is stripped from the counter names.
+func (fs FS) NewNetICMP6() (NetICMP, error) {
+	snmp6, err := parseNetDevSNMP6File(fs.Path("net/snmp6"))
+	if err != nil {
+		return NetICMP{}, err
+	}
+
+	var (
+		counters = map[string]int64{}
+		stats    = map[string]int64{}
+	)
+	for name, v := range snmp6 {
+		if !strings.HasPrefix(name, "Icmp6") {
+			continue
+		}
+		name = strings.TrimPrefix(name, "Icmp6")
+		if strings.HasPrefix(name, "InType") || strings.HasPrefix(name, "OutType") {
+			counters[name] = v
+			continue
+		}
+		stats[name] = v
+	}
+
+	icmp := NetICMP{Stats: stats}
+	if icmp.InType, icmp.OutType, err = parseICMPTypes(counters, "6"); err != nil {
+		return NetICMP{}, err
+	}
+
+	return icmp, nil
+}
+
+// parseICMPTypes splits counters named like "InType3" and "OutType3" into
+// maps keyed by ICMP type.
+func parseICMPTypes(counters map[string]int64, version string) (map[int]int64, map[int]int64, error) {
+	var (
+		in  = map[int]int64{}
+		out = map[int]int64{}
+	)
+
+	for name, v := range counters {
+		var (
+			types  = in
+			prefix = "InType"
+		)
+		if strings.HasPrefix(name, "OutType") {
+			types, prefix = out, "OutType"
+		}
+		t, err := strconv.Atoi(strings.TrimPrefix(name, prefix))
+		if err != nil {
+			return nil, nil, fmt.Errorf("couldn't parse ICMP%s type of %s: %s", version, name, err)
+		}
+		types[t] = v
+	}
+
+	return in, out, nil
+}
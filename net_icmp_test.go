@@ -0,0 +1,66 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import "testing"
+
+func TestNetICMP(t *testing.T) {
+	icmp, err := FS("fixtures").NewNetICMP()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		want int64
+		have int64
+	}{
+		{name: "InMsgs", want: 121, have: icmp.Stats["InMsgs"]},
+		{name: "InType3", want: 104, have: icmp.InType[3]},
+		{name: "OutType3", want: 128, have: icmp.OutType[3]},
+		{name: "InType11", want: 4, have: icmp.InType[11]},
+	} {
+		if tt.want != tt.have {
+			t.Errorf("want %s %d, have %d", tt.name, tt.want, tt.have)
+		}
+	}
+	if want, have := 4, len(icmp.InType); want != have {
+		t.Errorf("want %d received ICMP types, have %d", want, have)
+	}
+}
+
+func TestNetICMP6(t *testing.T) {
+	icmp, err := FS("fixtures").NewNetICMP6()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		want int64
+		have int64
+	}{
+		{name: "InErrors", want: 3, have: icmp.Stats["InErrors"]},
+		{name: "InType1", want: 7, have: icmp.InType[1]},
+		{name: "OutType1", want: 12, have: icmp.OutType[1]},
+		{name: "OutType143", want: 173, have: icmp.OutType[143]},
+	} {
+		if tt.want != tt.have {
+			t.Errorf("want %s %d, have %d", tt.name, tt.want, tt.have)
+		}
+	}
+	if _, ok := icmp.Stats["InType1"]; ok {
+		t.Error("want per-type counters to be excluded from stats")
+	}
+}
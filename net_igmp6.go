@@ -0,0 +1,66 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+)
+
+// MulticastGroups returns the IPv6 multicast groups joined on the interface
+// iface, as read from /proc/net/igmp6. The result is empty if the interface
+// is unknown or has not joined any group.
+func (fs FS) MulticastGroups(iface string) ([]net.IP, error) {
+	f, err := os.Open(fs.Path("net/igmp6"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseIGMP6(f, iface)
+}
+
+func parseIGMP6(r io.Reader, iface string) ([]net.IP, error) {
+	var (
+		groups  = []net.IP{}
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		// Lines look like
+		// "2    eth0            ff020000000000000000000000000001     1 0000000C 0".
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("invalid number of fields when parsing igmp6: %q", scanner.Text())
+		}
+		if fields[1] != iface {
+			continue
+		}
+
+		// Unlike the socket tables, the address is printed in network byte
+		// order.
+		b, err := hex.DecodeString(fields[2])
+		if err != nil || len(b) != net.IPv6len {
+			return nil, fmt.Errorf("invalid multicast group address: %s", fields[2])
+		}
+		groups = append(groups, net.IP(b))
+	}
+
+	return groups, scanner.Err()
+}
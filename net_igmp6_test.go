@@ -0,0 +1,49 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"net"
+	"testing"
+)
+
+func TestMulticastGroups(t *testing.T) {
+	groups, err := FS("fixtures").MulticastGroups("eth0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []net.IP{
+		net.ParseIP("ff02::1:ff8e:3c21"),
+		net.ParseIP("ff02::1"),
+		net.ParseIP("ff01::1"),
+		net.ParseIP("ff05::2"),
+	}
+	if len(want) != len(groups) {
+		t.Fatalf("want %d multicast groups, have %d", len(want), len(groups))
+	}
+	for i := range want {
+		if !want[i].Equal(groups[i]) {
+			t.Errorf("want multicast group %s, have %s", want[i], groups[i])
+		}
+	}
+
+	groups, err = FS("fixtures").MulticastGroups("wlan0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 0, len(groups); want != have {
+		t.Errorf("want %d multicast groups for unknown interface, have %d", want, have)
+	}
+}
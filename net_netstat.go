@@ -0,0 +1,79 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NewNetstat reads the extended network statistics from /proc/net/netstat,
+// keyed by protocol (e.g. "TcpExt") and counter name.
+func NewNetstat() (map[string]map[string]int64, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetstat()
+}
+
+// NewNetstat reads the extended network statistics from the specified `proc`
+// filesystem.
+func (fs FS) NewNetstat() (map[string]map[string]int64, error) {
+	f, err := os.Open(fs.Path("net/netstat"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseNetProtoStats(f)
+}
+
+// parseNetProtoStats parses files like /proc/net/netstat, which consist of
+// pairs of lines per protocol: a header line with the counter names followed
+// by a line with their values, both prefixed with the protocol name.
+func parseNetProtoStats(r io.Reader) (map[string]map[string]int64, error) {
+	var (
+		stats   = map[string]map[string]int64{}
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		names := strings.Fields(scanner.Text())
+		if !scanner.Scan() {
+			return nil, fmt.Errorf("missing values for header: %q", names)
+		}
+		values := strings.Fields(scanner.Text())
+		if len(names) == 0 || len(names) != len(values) || names[0] != values[0] {
+			return nil, fmt.Errorf("mismatched header and values: %q %q", names, values)
+		}
+
+		proto := strings.TrimSuffix(names[0], ":")
+		stats[proto] = make(map[string]int64, len(names)-1)
+		for i := 1; i < len(names); i++ {
+			v, err := strconv.ParseInt(values[i], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse %s (%s.%s): %s", values[i], proto, names[i], err)
+			}
+			stats[proto][names[i]] = v
+		}
+	}
+
+	return stats, scanner.Err()
+}
@@ -0,0 +1,95 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import "net"
+
+// NetRaw represents a single socket line of /proc/net/raw or /proc/net/raw6.
+type NetRaw struct {
+	// The local IP address of the socket.
+	LocalAddr net.IP
+	// The remote IP address of the socket, if connected.
+	RemoteAddr net.IP
+	// The IP protocol number the socket was opened for, e.g. 1 for ICMP.
+	Protocol uint16
+	// The socket state, as defined in include/net/tcp_states.h.
+	State uint64
+	// Amount of data in the transmit queue.
+	TxQueue uint64
+	// Amount of data in the receive queue.
+	RxQueue uint64
+	// The effective UID of the socket owner.
+	UID uint64
+	// The inode of the socket.
+	Inode uint64
+	// Number of packets dropped by the socket.
+	Drops uint64
+}
+
+// NewNetRaw reads the raw IP sockets from /proc/net/raw.
+func NewNetRaw() ([]NetRaw, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetRaw()
+}
+
+// NewNetRaw reads the raw IP sockets from the specified `proc` filesystem.
+func (fs FS) NewNetRaw() ([]NetRaw, error) {
+	return newNetRaw(fs.Path("net/raw"))
+}
+
+// NewNetRaw6 reads the raw IPv6 sockets from /proc/net/raw6.
+func NewNetRaw6() ([]NetRaw, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetRaw6()
+}
+
+// NewNetRaw6 reads the raw IPv6 sockets from the specified `proc`
+// filesystem.
+func (fs FS) NewNetRaw6() ([]NetRaw, error) {
+	return newNetRaw(fs.Path("net/raw6"))
+}
+
+// newNetRaw reads a raw socket table. It has the same layout as the UDP one,
+// with the protocol number in place of the local port.
+func newNetRaw(file string) ([]NetRaw, error) {
+	sockets, err := newNetUDP(file)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]NetRaw, 0, len(sockets))
+	for _, s := range sockets {
+		raw = append(raw, NetRaw{
+			LocalAddr:  s.LocalAddr,
+			RemoteAddr: s.RemoteAddr,
+			Protocol:   s.LocalPort,
+			State:      s.State,
+			TxQueue:    s.TxQueue,
+			RxQueue:    s.RxQueue,
+			UID:        s.UID,
+			Inode:      s.Inode,
+			Drops:      s.Drops,
+		})
+	}
+
+	return raw, nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import "testing"
+
+func TestNetRaw(t *testing.T) {
+	sockets, err := FS("fixtures").NewNetRaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 2, len(sockets); want != have {
+		t.Fatalf("want %d sockets, have %d", want, have)
+	}
+
+	// An ICMP socket opened by ping.
+	s := sockets[0]
+	if want, have := uint16(1), s.Protocol; want != have {
+		t.Errorf("want protocol %d, have %d", want, have)
+	}
+	if want, have := uint64(1000), s.UID; want != have {
+		t.Errorf("want uid %d, have %d", want, have)
+	}
+	if want, have := uint64(48219), s.Inode; want != have {
+		t.Errorf("want inode %d, have %d", want, have)
+	}
+	if want, have := uint16(255), sockets[1].Protocol; want != have {
+		t.Errorf("want protocol %d, have %d", want, have)
+	}
+}
+
+func TestNetRaw6(t *testing.T) {
+	sockets, err := FS("fixtures").NewNetRaw6()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 1, len(sockets); want != have {
+		t.Fatalf("want %d sockets, have %d", want, have)
+	}
+	if want, have := uint16(58), sockets[0].Protocol; want != have {
+		t.Errorf("want protocol %d, have %d", want, have)
+	}
+}
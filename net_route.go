@@ -0,0 +1,175 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Route flags as defined in include/uapi/linux/route.h.
+const (
+	// RouteFlagUp is set if the route is usable.
+	RouteFlagUp = 0x0001
+	// RouteFlagGateway is set if the destination is reached via a gateway.
+	RouteFlagGateway = 0x0002
+)
+
+// NetRoute represents a single line of /proc/net/route, the IPv4 routing
+// table of the kernel.
+type NetRoute struct {
+	// Name of the outgoing interface.
+	Iface string
+	// Destination network.
+	Destination net.IP
+	// Gateway address, 0.0.0.0 for directly connected networks.
+	Gateway net.IP
+	// Route flags, see the RouteFlag* constants.
+	Flags uint64
+	// Number of references to the route.
+	RefCnt int64
+	// Number of lookups of the route.
+	Use uint64
+	// Distance to the destination.
+	Metric int64
+	// Netmask of the destination network.
+	Mask net.IPMask
+	// Maximum transmission unit of the route, 0 if unset.
+	MTU uint64
+	// Default TCP window size of the route, 0 if unset.
+	Window uint64
+	// Initial round trip time of the route, 0 if unset.
+	IRTT uint64
+}
+
+// NewNetRoute reads the IPv4 routing table.
+func NewNetRoute() ([]NetRoute, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetRoute()
+}
+
+// NewNetRoute reads the IPv4 routing table from the specified `proc`
+// filesystem.
+func (fs FS) NewNetRoute() ([]NetRoute, error) {
+	f, err := os.Open(fs.Path("net/route"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseNetRoute(f)
+}
+
+// RouteFor returns the route the kernel would most likely pick to reach the
+// destination dst: the usable route with the longest matching prefix, and
+// among those the one with the lowest metric. Policy routing is not taken
+// into account. Only IPv4 destinations are supported.
+func (fs FS) RouteFor(dst net.IP) (NetRoute, error) {
+	dst4 := dst.To4()
+	if dst4 == nil {
+		return NetRoute{}, fmt.Errorf("unsupported destination %s: only IPv4 is supported", dst)
+	}
+
+	routes, err := fs.NewNetRoute()
+	if err != nil {
+		return NetRoute{}, err
+	}
+
+	var (
+		best     NetRoute
+		bestOnes = -1
+	)
+	for _, r := range routes {
+		if r.Flags&RouteFlagUp == 0 || !dst4.Mask(r.Mask).Equal(r.Destination) {
+			continue
+		}
+
+		ones, _ := r.Mask.Size()
+		if ones > bestOnes || (ones == bestOnes && r.Metric < best.Metric) {
+			best, bestOnes = r, ones
+		}
+	}
+	if bestOnes == -1 {
+		return NetRoute{}, fmt.Errorf("no route to %s", dst)
+	}
+
+	return best, nil
+}
+
+func parseNetRoute(r io.Reader) ([]NetRoute, error) {
+	var (
+		routes  = []NetRoute{}
+		scanner = bufio.NewScanner(r)
+	)
+
+	// Skip the header line.
+	scanner.Scan()
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 11 {
+			return nil, fmt.Errorf("invalid number of fields when parsing route: %q", scanner.Text())
+		}
+
+		var (
+			route = NetRoute{Iface: fields[0]}
+			mask  net.IP
+			err   error
+		)
+		if route.Destination, err = parseNetHexIP(fields[1]); err != nil {
+			return nil, err
+		}
+		if route.Gateway, err = parseNetHexIP(fields[2]); err != nil {
+			return nil, err
+		}
+		if route.Flags, err = strconv.ParseUint(fields[3], 16, 64); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (flags): %s", fields[3], err)
+		}
+		if route.RefCnt, err = strconv.ParseInt(fields[4], 10, 64); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (refcnt): %s", fields[4], err)
+		}
+		if route.Use, err = strconv.ParseUint(fields[5], 10, 64); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (use): %s", fields[5], err)
+		}
+		if route.Metric, err = strconv.ParseInt(fields[6], 10, 64); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (metric): %s", fields[6], err)
+		}
+		if mask, err = parseNetHexIP(fields[7]); err != nil {
+			return nil, err
+		}
+		route.Mask = net.IPMask(mask)
+		if route.MTU, err = strconv.ParseUint(fields[8], 10, 64); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (mtu): %s", fields[8], err)
+		}
+		if route.Window, err = strconv.ParseUint(fields[9], 10, 64); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (window): %s", fields[9], err)
+		}
+		if route.IRTT, err = strconv.ParseUint(fields[10], 10, 64); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (irtt): %s", fields[10], err)
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes, scanner.Err()
+}
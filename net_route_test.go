@@ -0,0 +1,80 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"net"
+	"testing"
+)
+
+func TestNetRoute(t *testing.T) {
+	routes, err := FS("fixtures").NewNetRoute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 6, len(routes); want != have {
+		t.Fatalf("want %d routes, have %d", want, have)
+	}
+
+	r := routes[0]
+	if want, have := "eth0", r.Iface; want != have {
+		t.Errorf("want interface %s, have %s", want, have)
+	}
+	if want, have := net.IPv4(192, 168, 2, 1), r.Gateway; !want.Equal(have) {
+		t.Errorf("want gateway %s, have %s", want, have)
+	}
+	if want, have := uint64(RouteFlagUp|RouteFlagGateway), r.Flags; want != have {
+		t.Errorf("want flags %#x, have %#x", want, have)
+	}
+
+	r = routes[2]
+	if want, have := net.IPv4(192, 168, 2, 0), r.Destination; !want.Equal(have) {
+		t.Errorf("want destination %s, have %s", want, have)
+	}
+	if ones, _ := r.Mask.Size(); ones != 24 {
+		t.Errorf("want prefix length 24, have %d", ones)
+	}
+}
+
+func TestRouteFor(t *testing.T) {
+	for _, test := range []struct {
+		dst     net.IP
+		iface   string
+		gateway net.IP
+	}{
+		// The connected /24 wins over the default routes.
+		{dst: net.IPv4(192, 168, 2, 77), iface: "eth0", gateway: net.IPv4zero},
+		// The /17 wins over the /8 of the same interface.
+		{dst: net.IPv4(10, 0, 200, 1), iface: "tun0", gateway: net.IPv4(10, 0, 0, 1)},
+		{dst: net.IPv4(10, 1, 2, 3), iface: "tun0", gateway: net.IPv4zero},
+		// The default route with the lowest metric wins.
+		{dst: net.IPv4(8, 8, 8, 8), iface: "eth0", gateway: net.IPv4(192, 168, 2, 1)},
+	} {
+		r, err := FS("fixtures").RouteFor(test.dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, have := test.iface, r.Iface; want != have {
+			t.Errorf("want %s routed via %s, have %s", test.dst, want, have)
+		}
+		if want, have := test.gateway, r.Gateway; !want.Equal(have) {
+			t.Errorf("want %s routed via gateway %s, have %s", test.dst, want, have)
+		}
+	}
+
+	if _, err := FS("fixtures").RouteFor(net.ParseIP("2001:db8::1")); err == nil {
+		t.Error("want RouteFor to fail for an IPv6 destination")
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import "os"
+
+// NetSNMP holds the network statistics of /proc/net/snmp, keyed by protocol
+// (e.g. "Ip", "Icmp", "Tcp" or "Udp") and counter name. Some counters, like
+// Tcp MaxConn, are signed and may be negative.
+type NetSNMP map[string]map[string]int64
+
+// NewNetSNMP reads the network statistics from /proc/net/snmp.
+func NewNetSNMP() (NetSNMP, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetSNMP()
+}
+
+// NewNetSNMP reads the network statistics from the specified `proc`
+// filesystem.
+func (fs FS) NewNetSNMP() (NetSNMP, error) {
+	f, err := os.Open(fs.Path("net/snmp"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseNetProtoStats(f)
+}
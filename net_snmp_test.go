@@ -0,0 +1,39 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import "testing"
+
+func TestNetSNMP(t *testing.T) {
+	snmp, err := FS("fixtures").NewNetSNMP()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		proto string
+		name  string
+		want  int64
+	}{
+		{proto: "Ip", name: "InReceives", want: 5207549},
+		{proto: "Icmp", name: "OutEchoReps", want: 7},
+		{proto: "Tcp", name: "RetransSegs", want: 6352},
+		{proto: "Tcp", name: "MaxConn", want: -1},
+		{proto: "Udp", name: "IgnoredMulti", want: 1402},
+	} {
+		if have := snmp[tt.proto][tt.name]; tt.want != have {
+			t.Errorf("want %s %s %d, have %d", tt.proto, tt.name, tt.want, have)
+		}
+	}
+}
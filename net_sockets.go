@@ -0,0 +1,73 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import "os"
+
+// SocketCounts holds the number of IPv4 and IPv6 sockets of a user by state
+// name, e.g. "ESTABLISHED". Unconnected UDP sockets are in state "CLOSE",
+// sockets in a state unknown to this package in state "UNKNOWN".
+type SocketCounts struct {
+	TCP map[string]int
+	UDP map[string]int
+}
+
+// SocketsByUID returns the number of TCP and UDP sockets of each user, keyed
+// by the effective UID of the socket owner.
+func (fs FS) SocketsByUID() (map[int]SocketCounts, error) {
+	var (
+		counts = map[int]SocketCounts{}
+		add    = func(uid uint64, tcp bool, state uint64) {
+			c, ok := counts[int(uid)]
+			if !ok {
+				c = SocketCounts{TCP: map[string]int{}, UDP: map[string]int{}}
+				counts[int(uid)] = c
+			}
+			if tcp {
+				c.TCP[tcpStateName(state)]++
+			} else {
+				c.UDP[tcpStateName(state)]++
+			}
+		}
+	)
+
+	for _, file := range []string{"net/tcp", "net/tcp6"} {
+		sockets, err := newNetTCP(fs.Path(file))
+		if err != nil {
+			// IPv6 might be disabled.
+			if file == "net/tcp6" && os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, s := range sockets {
+			add(s.UID, true, s.State)
+		}
+	}
+
+	for _, file := range []string{"net/udp", "net/udp6"} {
+		sockets, err := newNetUDP(fs.Path(file))
+		if err != nil {
+			if file == "net/udp6" && os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, s := range sockets {
+			add(s.UID, false, s.State)
+		}
+	}
+
+	return counts, nil
+}
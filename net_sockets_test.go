@@ -0,0 +1,62 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSocketsByUID(t *testing.T) {
+	counts, err := FS("fixtures").SocketsByUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 5, len(counts); want != have {
+		t.Fatalf("want sockets of %d users, have %d", want, have)
+	}
+
+	want := SocketCounts{
+		TCP: map[string]int{"ESTABLISHED": 2, "LISTEN": 1},
+		UDP: map[string]int{"ESTABLISHED": 1},
+	}
+	if have := counts[1000]; !reflect.DeepEqual(want, have) {
+		t.Errorf("want sockets of uid 1000 %v, have %v", want, have)
+	}
+
+	root := counts[0]
+	if want, have := 1, root.TCP["ESTABLISHED"]; want != have {
+		t.Errorf("want %d established tcp sockets of uid 0, have %d", want, have)
+	}
+	if want, have := 2, root.UDP["CLOSE"]; want != have {
+		t.Errorf("want %d unconnected udp sockets of uid 0, have %d", want, have)
+	}
+}
+
+func TestSocketsByUIDUnknownState(t *testing.T) {
+	// The second TCP socket is in state 0x0D, which isn't defined.
+	counts, err := FS("fixtures/sockstates").SocketsByUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[int]SocketCounts{1000: {
+		TCP: map[string]int{"ESTABLISHED": 1, "UNKNOWN": 1},
+		UDP: map[string]int{"CLOSE": 1},
+	}}
+	if !reflect.DeepEqual(want, counts) {
+		t.Errorf("want sockets %v, have %v", want, counts)
+	}
+}
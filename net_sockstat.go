@@ -0,0 +1,156 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// orphanNearLimit is the utilization of net.ipv4.tcp_max_orphans in percent
+// above which OrphanReport.NearLimit is set.
+const orphanNearLimit = 90
+
+// OrphanReport relates the number of orphaned TCP sockets, i.e. sockets no
+// longer attached to a file descriptor, to the net.ipv4.tcp_max_orphans limit.
+// Beyond the limit the kernel resets orphaned connections instead of closing
+// them gracefully.
+type OrphanReport struct {
+	// Number of orphaned TCP sockets.
+	Orphans int64
+	// Value of net.ipv4.tcp_max_orphans.
+	MaxOrphans int64
+	// Orphans in percent of MaxOrphans.
+	Utilization float64
+	// Whether the utilization is at or above 90 percent.
+	NearLimit bool
+}
+
+// NewNetSockstat reads the socket statistics from /proc/net/sockstat, keyed
+// by protocol (e.g. "TCP") and counter name.
+func NewNetSockstat() (map[string]map[string]int64, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetSockstat()
+}
+
+// NewNetSockstat reads the socket statistics from the specified `proc`
+// filesystem.
+func (fs FS) NewNetSockstat() (map[string]map[string]int64, error) {
+	f, err := os.Open(fs.Path("net/sockstat"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseNetSockstat(f)
+}
+
+// TCPMemUtilization returns the memory used by TCP sockets as a ratio of the
+// high watermark of net.ipv4.tcp_mem, above which the kernel refuses to
+// allocate further socket buffers.
+func (fs FS) TCPMemUtilization() (float64, error) {
+	sockstat, err := fs.NewNetSockstat()
+	if err != nil {
+		return 0, err
+	}
+	mem, ok := sockstat["TCP"]["mem"]
+	if !ok {
+		return 0, fmt.Errorf("TCP mem not found in sockstat")
+	}
+
+	data, err := ioutil.ReadFile(fs.Path("sys/net/ipv4/tcp_mem"))
+	if err != nil {
+		return 0, err
+	}
+	// The file holds the low, pressure and high watermark in pages.
+	limits := strings.Fields(string(data))
+	if len(limits) != 3 {
+		return 0, fmt.Errorf("unexpected tcp_mem format: %q", data)
+	}
+	high, err := strconv.ParseInt(limits[2], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't parse %s (tcp_mem): %s", limits[2], err)
+	}
+	if high == 0 {
+		return 0, fmt.Errorf("tcp_mem high watermark is zero")
+	}
+
+	return float64(mem) / float64(high), nil
+}
+
+// OrphanSockets returns the number of orphaned TCP sockets in relation to
+// net.ipv4.tcp_max_orphans.
+func (fs FS) OrphanSockets() (OrphanReport, error) {
+	sockstat, err := fs.NewNetSockstat()
+	if err != nil {
+		return OrphanReport{}, err
+	}
+	orphans, ok := sockstat["TCP"]["orphan"]
+	if !ok {
+		return OrphanReport{}, fmt.Errorf("TCP orphan not found in sockstat")
+	}
+
+	max, err := readSysctlInt(fs.Path("sys/net/ipv4/tcp_max_orphans"))
+	if err != nil {
+		return OrphanReport{}, err
+	}
+	if max == 0 {
+		return OrphanReport{}, fmt.Errorf("tcp_max_orphans is zero")
+	}
+
+	r := OrphanReport{
+		Orphans:     orphans,
+		MaxOrphans:  max,
+		Utilization: float64(orphans) / float64(max) * 100,
+	}
+	r.NearLimit = r.Utilization >= orphanNearLimit
+
+	return r, nil
+}
+
+func parseNetSockstat(r io.Reader) (map[string]map[string]int64, error) {
+	var (
+		stats   = map[string]map[string]int64{}
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		// Lines look like "TCP: inuse 4 orphan 0 tw 4 alloc 17 mem 1".
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || len(fields)%2 != 1 || !strings.HasSuffix(fields[0], ":") {
+			return nil, fmt.Errorf("malformed sockstat line: %q", scanner.Text())
+		}
+
+		proto := strings.TrimSuffix(fields[0], ":")
+		stats[proto] = make(map[string]int64, len(fields)/2)
+		for i := 1; i < len(fields); i += 2 {
+			v, err := strconv.ParseInt(fields[i+1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse %s (%s %s): %s", fields[i+1], proto, fields[i], err)
+			}
+			stats[proto][fields[i]] = v
+		}
+	}
+
+	return stats, scanner.Err()
+}
@@ -0,0 +1,71 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import "testing"
+
+func TestNetSockstat(t *testing.T) {
+	sockstat, err := FS("fixtures").NewNetSockstat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		proto string
+		name  string
+		want  int64
+	}{
+		{proto: "sockets", name: "used", want: 229},
+		{proto: "TCP", name: "alloc", want: 17},
+		{proto: "TCP", name: "mem", want: 47010},
+		{proto: "UDP", name: "inuse", want: 2},
+	} {
+		if have := sockstat[tt.proto][tt.name]; tt.want != have {
+			t.Errorf("want %s %s %d, have %d", tt.proto, tt.name, tt.want, have)
+		}
+	}
+}
+
+func TestTCPMemUtilization(t *testing.T) {
+	ratio, err := FS("fixtures").TCPMemUtilization()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 0.5, ratio; want != have {
+		t.Errorf("want tcp memory utilization %f, have %f", want, have)
+	}
+}
+
+func TestOrphanSockets(t *testing.T) {
+	for _, tt := range []struct {
+		root string
+		want OrphanReport
+	}{
+		{root: "fixtures", want: OrphanReport{Orphans: 0, MaxOrphans: 8192}},
+		{root: "fixtures/orphans/near", want: OrphanReport{Orphans: 7900, MaxOrphans: 8192, Utilization: 7900.0 / 8192 * 100, NearLimit: true}},
+	} {
+		have, err := FS(tt.root).OrphanSockets()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.want != have {
+			t.Errorf("want %s orphan report %+v, have %+v", tt.root, tt.want, have)
+		}
+	}
+
+	if _, err := FS("fixtures/orphans/nolimit").OrphanSockets(); err == nil {
+		t.Error("want error for zero tcp_max_orphans")
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SynBacklogReport relates the number of half-open TCP connections to the
+// net.ipv4.tcp_max_syn_backlog limit. A growing ratio is a leading indicator
+// of a SYN flood or of an application not accepting connections fast enough.
+type SynBacklogReport struct {
+	// Number of IPv4 and IPv6 sockets in state SYN_RECV.
+	SynRecv int64
+	// Value of net.ipv4.tcp_max_syn_backlog.
+	MaxSynBacklog int64
+	// SynRecv as a ratio of MaxSynBacklog.
+	Ratio float64
+	// Value of net.ipv4.tcp_syncookies: 0 if disabled, 1 if SYN cookies are
+	// sent once the backlog overflows and 2 if they are always sent. With
+	// SYN cookies enabled a full backlog doesn't drop connections.
+	Syncookies int64
+}
+
+// SynRecvRatio returns the number of sockets in state SYN_RECV as a ratio of
+// net.ipv4.tcp_max_syn_backlog.
+func (fs FS) SynRecvRatio() (float64, error) {
+	r, err := fs.NewSynBacklogReport()
+	if err != nil {
+		return 0, err
+	}
+
+	return r.Ratio, nil
+}
+
+// NewSynBacklogReport creates a SynBacklogReport from the specified `proc`
+// filesystem.
+func (fs FS) NewSynBacklogReport() (SynBacklogReport, error) {
+	var r SynBacklogReport
+
+	for _, file := range []string{"net/tcp", "net/tcp6"} {
+		sockets, err := newNetTCP(fs.Path(file))
+		if err != nil {
+			// IPv6 might be disabled.
+			if file == "net/tcp6" && os.IsNotExist(err) {
+				continue
+			}
+			return SynBacklogReport{}, err
+		}
+		for _, s := range sockets {
+			if s.State == TCPStateSynRecv {
+				r.SynRecv++
+			}
+		}
+	}
+
+	var err error
+	if r.MaxSynBacklog, err = readSysctlInt(fs.Path("sys/net/ipv4/tcp_max_syn_backlog")); err != nil {
+		return SynBacklogReport{}, err
+	}
+	if r.MaxSynBacklog == 0 {
+		return SynBacklogReport{}, fmt.Errorf("tcp_max_syn_backlog is zero")
+	}
+	if r.Syncookies, err = readSysctlInt(fs.Path("sys/net/ipv4/tcp_syncookies")); err != nil {
+		return SynBacklogReport{}, err
+	}
+	r.Ratio = float64(r.SynRecv) / float64(r.MaxSynBacklog)
+
+	return r, nil
+}
+
+func readSysctlInt(path string) (int64, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't parse %s (%s): %s", strings.TrimSpace(string(data)), filepath.Base(path), err)
+	}
+
+	return v, nil
+}
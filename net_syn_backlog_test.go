@@ -0,0 +1,45 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import "testing"
+
+func TestNewSynBacklogReport(t *testing.T) {
+	for _, tt := range []struct {
+		root string
+		want SynBacklogReport
+	}{
+		{root: "fixtures", want: SynBacklogReport{SynRecv: 0, MaxSynBacklog: 512, Syncookies: 1}},
+		{root: "fixtures/synflood", want: SynBacklogReport{SynRecv: 8, MaxSynBacklog: 10, Ratio: 0.8, Syncookies: 0}},
+	} {
+		have, err := FS(tt.root).NewSynBacklogReport()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.want != have {
+			t.Errorf("want %s syn backlog report %+v, have %+v", tt.root, tt.want, have)
+		}
+	}
+}
+
+func TestSynRecvRatio(t *testing.T) {
+	ratio, err := FS("fixtures/synflood").SynRecvRatio()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 0.8, ratio; want != have {
+		t.Errorf("want syn recv ratio %f, have %f", want, have)
+	}
+}
@@ -0,0 +1,344 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TCP socket states as defined in include/net/tcp_states.h.
+const (
+	// TCPStateSynRecv is the state of a socket which received a SYN and
+	// waits for the final ACK of the handshake.
+	TCPStateSynRecv = 0x03
+	// TCPStateListen is the state of a listening socket.
+	TCPStateListen = 0x0A
+)
+
+// TCP socket timers as reported in the tr column of /proc/net/tcp.
+const (
+	TCPTimerNone            = 0
+	TCPTimerRetransmit      = 1
+	TCPTimerKeepalive       = 2
+	TCPTimerTimeWait        = 3
+	TCPTimerZeroWindowProbe = 4
+)
+
+// tcpStateNames maps the TCP socket states of include/net/tcp_states.h to
+// their names.
+var tcpStateNames = map[uint64]string{
+	0x01: "ESTABLISHED",
+	0x02: "SYN_SENT",
+	0x03: "SYN_RECV",
+	0x04: "FIN_WAIT1",
+	0x05: "FIN_WAIT2",
+	0x06: "TIME_WAIT",
+	0x07: "CLOSE",
+	0x08: "CLOSE_WAIT",
+	0x09: "LAST_ACK",
+	0x0A: "LISTEN",
+	0x0B: "CLOSING",
+	0x0C: "NEW_SYN_RECV",
+}
+
+// NetTCP represents a single socket line of /proc/net/tcp or /proc/net/tcp6.
+type NetTCP struct {
+	// The local IP address of the socket.
+	LocalAddr net.IP
+	// The local port of the socket.
+	LocalPort uint16
+	// The remote IP address of the socket.
+	RemoteAddr net.IP
+	// The remote port of the socket.
+	RemotePort uint16
+	// The socket state, as defined in include/net/tcp_states.h.
+	State uint64
+	// Amount of data in the transmit queue.
+	TxQueue uint64
+	// Amount of data in the receive queue.
+	RxQueue uint64
+	// The pending timer of the socket, see the TCPTimer* constants.
+	Timer uint64
+	// Time until the timer expires in clock ticks (USER_HZ).
+	TimerTicks uint64
+	// The effective UID of the socket owner.
+	UID uint64
+	// The inode of the socket.
+	Inode uint64
+}
+
+// TimerRemaining returns the time until the pending timer of the socket
+// expires.
+func (s NetTCP) TimerRemaining() time.Duration {
+	return time.Duration(s.TimerTicks) * time.Second / userHZ
+}
+
+// TCPRetransReport relates the system wide count of lost retransmissions to
+// the TCP listeners of the system.
+type TCPRetransReport struct {
+	// Value of TcpExt.TCPLostRetransmit from /proc/net/netstat.
+	LostRetransmits int64
+	// Local ports of all listening TCP sockets, IPv4 and IPv6, in ascending
+	// order.
+	ListeningPorts []uint16
+	// Whether the lost retransmissions increased by more than the threshold
+	// since the previous report.
+	RetransPressure bool
+}
+
+// NewNetTCP reads the TCP sockets from /proc/net/tcp.
+func NewNetTCP() ([]NetTCP, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetTCP()
+}
+
+// NewNetTCP reads the TCP sockets from the specified `proc` filesystem.
+func (fs FS) NewNetTCP() ([]NetTCP, error) {
+	return newNetTCP(fs.Path("net/tcp"))
+}
+
+// NewNetTCP6 reads the TCP sockets from /proc/net/tcp6.
+func NewNetTCP6() ([]NetTCP, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetTCP6()
+}
+
+// NewNetTCP6 reads the TCP over IPv6 sockets from the specified `proc`
+// filesystem.
+func (fs FS) NewNetTCP6() ([]NetTCP, error) {
+	return newNetTCP(fs.Path("net/tcp6"))
+}
+
+// NewTCPRetransReport creates a TCPRetransReport from the specified `proc`
+// filesystem. RetransPressure is set if TcpExt.TCPLostRetransmit grew by more
+// than threshold since prev; pass the zero value as prev to compare against
+// the counter since boot.
+//
+// The kernel does not account retransmissions per connection in
+// /proc/net/tcp, so this is a system level signal. The listening ports only
+// give context on which services might be affected.
+func (fs FS) NewTCPRetransReport(prev TCPRetransReport, threshold int64) (TCPRetransReport, error) {
+	netstat, err := fs.NewNetstat()
+	if err != nil {
+		return TCPRetransReport{}, err
+	}
+	lost, ok := netstat["TcpExt"]["TCPLostRetransmit"]
+	if !ok {
+		return TCPRetransReport{}, fmt.Errorf("TcpExt.TCPLostRetransmit not found in netstat")
+	}
+
+	report := TCPRetransReport{
+		LostRetransmits: lost,
+		RetransPressure: lost-prev.LostRetransmits > threshold,
+	}
+
+	listeners, err := fs.ListeningPorts()
+	if err != nil {
+		return TCPRetransReport{}, err
+	}
+	for port := range listeners {
+		report.ListeningPorts = append(report.ListeningPorts, port)
+	}
+	sort.Slice(report.ListeningPorts, func(i, j int) bool {
+		return report.ListeningPorts[i] < report.ListeningPorts[j]
+	})
+
+	return report, nil
+}
+
+// ListeningPorts returns the listening IPv4 and IPv6 TCP sockets keyed by
+// local port. If a port is listened on by both an IPv4 and an IPv6 socket,
+// e.g. by a dual-stack server, the IPv6 socket is returned.
+func (fs FS) ListeningPorts() (map[uint16]NetTCP, error) {
+	listeners := map[uint16]NetTCP{}
+
+	// Read IPv6 last, so its sockets take precedence.
+	for _, file := range []string{"net/tcp", "net/tcp6"} {
+		sockets, err := newNetTCP(fs.Path(file))
+		if err != nil {
+			// IPv6 might be disabled.
+			if file == "net/tcp6" && os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, s := range sockets {
+			if s.State == TCPStateListen {
+				listeners[s.LocalPort] = s
+			}
+		}
+	}
+
+	return listeners, nil
+}
+
+// TCPStateCounts returns the number of IPv4 and IPv6 TCP sockets by state
+// name, e.g. "ESTABLISHED", counting states unknown to this package as
+// "UNKNOWN". In contrast to NewNetTCP only the state column is decoded, so
+// memory use doesn't grow with the number of sockets.
+func (fs FS) TCPStateCounts() (map[string]int, error) {
+	counts := map[string]int{}
+	for _, file := range []string{"net/tcp", "net/tcp6"} {
+		err := countNetTCPStates(fs.Path(file), counts)
+		// IPv6 might be disabled.
+		if err != nil && !(file == "net/tcp6" && os.IsNotExist(err)) {
+			return nil, err
+		}
+	}
+
+	return counts, nil
+}
+
+func countNetTCPStates(file string, counts map[string]int) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	// Skip the header line.
+	scanner.Scan()
+
+	for scanner.Scan() {
+		st := nthField(scanner.Bytes(), 3)
+		if len(st) != 2 {
+			return fmt.Errorf("invalid state column when parsing tcp socket: %q", scanner.Text())
+		}
+		hi, lo := hexDigit(st[0]), hexDigit(st[1])
+		if hi == 0xff || lo == 0xff {
+			return fmt.Errorf("invalid state column when parsing tcp socket: %q", scanner.Text())
+		}
+		counts[tcpStateName(hi<<4|lo)]++
+	}
+
+	return scanner.Err()
+}
+
+// tcpStateName returns the name of a TCP socket state, or "UNKNOWN" for states
+// added by newer kernels.
+func tcpStateName(state uint64) string {
+	if name, ok := tcpStateNames[state]; ok {
+		return name
+	}
+
+	return "UNKNOWN"
+}
+
+// nthField returns the n-th whitespace separated field of line, counting from
+// zero, without allocating.
+func nthField(line []byte, n int) []byte {
+	for i := 0; i <= n; i++ {
+		line = bytes.TrimLeft(line, " \t")
+		end := bytes.IndexAny(line, " \t")
+		if end < 0 {
+			end = len(line)
+		}
+		if i == n {
+			return line[:end]
+		}
+		line = line[end:]
+	}
+
+	return nil
+}
+
+// hexDigit returns the value of a hex digit, or 0xff if c is none.
+func hexDigit(c byte) uint64 {
+	switch {
+	case '0' <= c && c <= '9':
+		return uint64(c - '0')
+	case 'a' <= c && c <= 'f':
+		return uint64(c - 'a' + 10)
+	case 'A' <= c && c <= 'F':
+		return uint64(c - 'A' + 10)
+	}
+
+	return 0xff
+}
+
+func newNetTCP(file string) ([]NetTCP, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseNetTCP(f)
+}
+
+func parseNetTCP(r io.Reader) ([]NetTCP, error) {
+	var (
+		sockets = []NetTCP{}
+		scanner = bufio.NewScanner(r)
+	)
+
+	// Skip the header line.
+	scanner.Scan()
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			return nil, fmt.Errorf("invalid number of fields when parsing tcp socket: %q", scanner.Text())
+		}
+
+		l, err := parseNetSocketLine(fields)
+		if err != nil {
+			return nil, err
+		}
+		s := NetTCP{
+			LocalAddr:  l.LocalAddr,
+			LocalPort:  l.LocalPort,
+			RemoteAddr: l.RemoteAddr,
+			RemotePort: l.RemotePort,
+			State:      l.State,
+			TxQueue:    l.TxQueue,
+			RxQueue:    l.RxQueue,
+			UID:        l.UID,
+			Inode:      l.Inode,
+		}
+		timer := strings.Split(fields[5], ":")
+		if len(timer) != 2 {
+			return nil, fmt.Errorf("unexpected tr:tm->when format: %s", fields[5])
+		}
+		if s.Timer, err = strconv.ParseUint(timer[0], 16, 64); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (tr): %s", timer[0], err)
+		}
+		if s.TimerTicks, err = strconv.ParseUint(timer[1], 16, 64); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (tm->when): %s", timer[1], err)
+		}
+
+		sockets = append(sockets, s)
+	}
+
+	return sockets, scanner.Err()
+}
@@ -0,0 +1,170 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNetTCP(t *testing.T) {
+	sockets, err := FS("fixtures").NewNetTCP()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 5, len(sockets); want != have {
+		t.Fatalf("want %d sockets, have %d", want, have)
+	}
+
+	s := sockets[3]
+	if want, have := net.IPv4(192, 168, 2, 15), s.LocalAddr; !want.Equal(have) {
+		t.Errorf("want local address %s, have %s", want, have)
+	}
+	if want, have := net.IPv4(93, 184, 216, 34), s.RemoteAddr; !want.Equal(have) {
+		t.Errorf("want remote address %s, have %s", want, have)
+	}
+	if want, have := uint16(443), s.RemotePort; want != have {
+		t.Errorf("want remote port %d, have %d", want, have)
+	}
+	if want, have := uint64(0x24), s.TxQueue; want != have {
+		t.Errorf("want tx_queue %d, have %d", want, have)
+	}
+	if want, have := uint64(TCPTimerRetransmit), s.Timer; want != have {
+		t.Errorf("want timer %d, have %d", want, have)
+	}
+	if want, have := 210*time.Millisecond, s.TimerRemaining(); want != have {
+		t.Errorf("want remaining timer %s, have %s", want, have)
+	}
+	if want, have := uint64(1000), s.UID; want != have {
+		t.Errorf("want uid %d, have %d", want, have)
+	}
+	if want, have := uint64(82113), s.Inode; want != have {
+		t.Errorf("want inode %d, have %d", want, have)
+	}
+}
+
+func TestNetTCP6(t *testing.T) {
+	sockets, err := FS("fixtures").NewNetTCP6()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 3, len(sockets); want != have {
+		t.Fatalf("want %d sockets, have %d", want, have)
+	}
+	if want, have := uint64(TCPStateListen), sockets[1].State; want != have {
+		t.Errorf("want state %d, have %d", want, have)
+	}
+	if want, have := uint16(8080), sockets[1].LocalPort; want != have {
+		t.Errorf("want local port %d, have %d", want, have)
+	}
+	if want, have := net.IPv4(192, 168, 2, 5), sockets[2].RemoteAddr; !want.Equal(have) {
+		t.Errorf("want remote address %s, have %s", want, have)
+	}
+}
+
+func TestNetstat(t *testing.T) {
+	netstat, err := FS("fixtures").NewNetstat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		proto string
+		name  string
+		want  int64
+	}{
+		{proto: "TcpExt", name: "TCPLostRetransmit", want: 1276},
+		{proto: "TcpExt", name: "TW", want: 388812},
+		{proto: "IpExt", name: "InOctets", want: 6286396970},
+	} {
+		if have := netstat[tt.proto][tt.name]; tt.want != have {
+			t.Errorf("want %s.%s %d, have %d", tt.proto, tt.name, tt.want, have)
+		}
+	}
+}
+
+func TestTCPRetransReport(t *testing.T) {
+	fs := FS("fixtures")
+
+	report, err := fs.NewTCPRetransReport(TCPRetransReport{LostRetransmits: 1200}, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := int64(1276), report.LostRetransmits; want != have {
+		t.Errorf("want lost retransmits %d, have %d", want, have)
+	}
+	if want, have := []uint16{22, 3306, 8080}, report.ListeningPorts; !reflect.DeepEqual(want, have) {
+		t.Errorf("want listening ports %v, have %v", want, have)
+	}
+	if !report.RetransPressure {
+		t.Error("want retransmit pressure")
+	}
+
+	report, err = fs.NewTCPRetransReport(report, 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.RetransPressure {
+		t.Error("want no retransmit pressure without new lost retransmits")
+	}
+}
+
+func TestListeningPorts(t *testing.T) {
+	listeners, err := FS("fixtures").ListeningPorts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 3, len(listeners); want != have {
+		t.Fatalf("want %d listening ports, have %d", want, have)
+	}
+
+	// sshd listens on port 22 with both an IPv4 and an IPv6 socket.
+	ssh, ok := listeners[22]
+	if !ok {
+		t.Fatal("want port 22 to be listening")
+	}
+	if want, have := uint64(15188), ssh.Inode; want != have {
+		t.Errorf("want port 22 inode %d, have %d", want, have)
+	}
+	if want, have := uint64(999), listeners[3306].UID; want != have {
+		t.Errorf("want port 3306 uid %d, have %d", want, have)
+	}
+}
+
+func TestTCPStateCounts(t *testing.T) {
+	counts, err := FS("fixtures").TCPStateCounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int{"LISTEN": 4, "ESTABLISHED": 3, "TIME_WAIT": 1}
+	if !reflect.DeepEqual(want, counts) {
+		t.Errorf("want tcp state counts %v, have %v", want, counts)
+	}
+	// The second socket is in state 0x0D, which isn't defined.
+	counts, err = FS("fixtures/sockstates").TCPStateCounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = map[string]int{"ESTABLISHED": 1, "UNKNOWN": 1}
+	if !reflect.DeepEqual(want, counts) {
+		t.Errorf("want tcp state counts %v, have %v", want, counts)
+	}
+}
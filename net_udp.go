@@ -0,0 +1,251 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NetUDP represents a single socket line of /proc/net/udp or /proc/net/udp6.
+type NetUDP struct {
+	// The local IP address of the socket.
+	LocalAddr net.IP
+	// The local port of the socket.
+	LocalPort uint16
+	// The remote IP address of the socket.
+	RemoteAddr net.IP
+	// The remote port of the socket.
+	RemotePort uint16
+	// The socket state, as defined in include/net/tcp_states.h.
+	State uint64
+	// Amount of data in the transmit queue.
+	TxQueue uint64
+	// Amount of data in the receive queue.
+	RxQueue uint64
+	// The effective UID of the socket owner.
+	UID uint64
+	// The inode of the socket.
+	Inode uint64
+	// Number of datagrams dropped by the socket.
+	Drops uint64
+}
+
+// NewNetUDP reads the UDP sockets from /proc/net/udp.
+func NewNetUDP() ([]NetUDP, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetUDP()
+}
+
+// NewNetUDP reads the UDP sockets from the specified `proc` filesystem.
+func (fs FS) NewNetUDP() ([]NetUDP, error) {
+	return newNetUDP(fs.Path("net/udp"))
+}
+
+// NewNetUDP6 reads the UDP sockets from /proc/net/udp6.
+func NewNetUDP6() ([]NetUDP, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewNetUDP6()
+}
+
+// NewNetUDP6 reads the UDP over IPv6 sockets from the specified `proc`
+// filesystem.
+func (fs FS) NewNetUDP6() ([]NetUDP, error) {
+	return newNetUDP(fs.Path("net/udp6"))
+}
+
+// NetUDPDropRate returns the number of datagrams dropped per local port
+// between two samples of /proc/net/udp. Sockets are matched by local port and
+// inode, so a socket which was recreated between both samples is accounted
+// with all of its drops rather than a bogus difference.
+func NetUDPDropRate(prev, cur []NetUDP) map[uint16]uint64 {
+	type socketKey struct {
+		port  uint16
+		inode uint64
+	}
+
+	previous := make(map[socketKey]uint64, len(prev))
+	for _, s := range prev {
+		previous[socketKey{s.LocalPort, s.Inode}] = s.Drops
+	}
+
+	drops := map[uint16]uint64{}
+	for _, s := range cur {
+		d := s.Drops
+		if p, ok := previous[socketKey{s.LocalPort, s.Inode}]; ok {
+			if d < p {
+				continue
+			}
+			d -= p
+		}
+		drops[s.LocalPort] += d
+	}
+
+	return drops
+}
+
+func newNetUDP(file string) ([]NetUDP, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseNetUDP(f)
+}
+
+func parseNetUDP(r io.Reader) ([]NetUDP, error) {
+	var (
+		sockets = []NetUDP{}
+		scanner = bufio.NewScanner(r)
+	)
+
+	// Skip the header line.
+	scanner.Scan()
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 13 {
+			return nil, fmt.Errorf("invalid number of fields when parsing udp socket: %q", scanner.Text())
+		}
+
+		l, err := parseNetSocketLine(fields)
+		if err != nil {
+			return nil, err
+		}
+		s := NetUDP{
+			LocalAddr:  l.LocalAddr,
+			LocalPort:  l.LocalPort,
+			RemoteAddr: l.RemoteAddr,
+			RemotePort: l.RemotePort,
+			State:      l.State,
+			TxQueue:    l.TxQueue,
+			RxQueue:    l.RxQueue,
+			UID:        l.UID,
+			Inode:      l.Inode,
+		}
+		if s.Drops, err = strconv.ParseUint(fields[12], 10, 64); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (drops): %s", fields[12], err)
+		}
+
+		sockets = append(sockets, s)
+	}
+
+	return sockets, scanner.Err()
+}
+
+// netSocketLine holds the columns common to the socket tables of TCP and UDP
+// under /proc/net.
+type netSocketLine struct {
+	LocalAddr  net.IP
+	LocalPort  uint16
+	RemoteAddr net.IP
+	RemotePort uint16
+	State      uint64
+	TxQueue    uint64
+	RxQueue    uint64
+	UID        uint64
+	Inode      uint64
+}
+
+// parseNetSocketLine parses the columns common to the TCP and UDP socket
+// tables from the fields of a line, which must have at least 10 fields.
+func parseNetSocketLine(fields []string) (netSocketLine, error) {
+	var (
+		l   netSocketLine
+		err error
+	)
+	if l.LocalAddr, l.LocalPort, err = parseNetIPSocketAddr(fields[1]); err != nil {
+		return netSocketLine{}, err
+	}
+	if l.RemoteAddr, l.RemotePort, err = parseNetIPSocketAddr(fields[2]); err != nil {
+		return netSocketLine{}, err
+	}
+	if l.State, err = strconv.ParseUint(fields[3], 16, 64); err != nil {
+		return netSocketLine{}, fmt.Errorf("couldn't parse %s (st): %s", fields[3], err)
+	}
+	queues := strings.Split(fields[4], ":")
+	if len(queues) != 2 {
+		return netSocketLine{}, fmt.Errorf("unexpected tx_queue:rx_queue format: %s", fields[4])
+	}
+	if l.TxQueue, err = strconv.ParseUint(queues[0], 16, 64); err != nil {
+		return netSocketLine{}, fmt.Errorf("couldn't parse %s (tx_queue): %s", queues[0], err)
+	}
+	if l.RxQueue, err = strconv.ParseUint(queues[1], 16, 64); err != nil {
+		return netSocketLine{}, fmt.Errorf("couldn't parse %s (rx_queue): %s", queues[1], err)
+	}
+	if l.UID, err = strconv.ParseUint(fields[7], 10, 64); err != nil {
+		return netSocketLine{}, fmt.Errorf("couldn't parse %s (uid): %s", fields[7], err)
+	}
+	if l.Inode, err = strconv.ParseUint(fields[9], 10, 64); err != nil {
+		return netSocketLine{}, fmt.Errorf("couldn't parse %s (inode): %s", fields[9], err)
+	}
+
+	return l, nil
+}
+
+// parseNetIPSocketAddr parses an address of the form "0100007F:0035" as found
+// in the socket tables under /proc/net. In contrast to the IPVS tables, the
+// address is printed in host byte order.
+func parseNetIPSocketAddr(s string) (net.IP, uint16, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return nil, 0, fmt.Errorf("unexpected IP:Port: %s", s)
+	}
+
+	ip, err := parseNetHexIP(parts[0])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	port, err := strconv.ParseUint(parts[1], 16, 16)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ip, uint16(port), nil
+}
+
+// parseNetHexIP parses an IPv4 or IPv6 address printed by the kernel as a
+// sequence of hex encoded 32-bit words in host byte order.
+func parseNetHexIP(s string) (net.IP, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != net.IPv4len && len(b) != net.IPv6len {
+		return nil, fmt.Errorf("invalid IP address: %s", s)
+	}
+
+	ip := make(net.IP, len(b))
+	for i := 0; i < len(b); i += 4 {
+		ip[i], ip[i+1], ip[i+2], ip[i+3] = b[i+3], b[i+2], b[i+1], b[i]
+	}
+
+	return ip, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"net"
+	"testing"
+)
+
+func TestNetUDP(t *testing.T) {
+	sockets, err := FS("fixtures").NewNetUDP()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 3, len(sockets); want != have {
+		t.Fatalf("want %d sockets, have %d", want, have)
+	}
+
+	s := sockets[1]
+	if want, have := net.IPv4(127, 0, 0, 53), s.LocalAddr; !want.Equal(have) {
+		t.Errorf("want local address %s, have %s", want, have)
+	}
+	if want, have := uint16(53), s.LocalPort; want != have {
+		t.Errorf("want local port %d, have %d", want, have)
+	}
+	if want, have := uint64(101), s.UID; want != have {
+		t.Errorf("want uid %d, have %d", want, have)
+	}
+	if want, have := uint64(21345), s.Inode; want != have {
+		t.Errorf("want inode %d, have %d", want, have)
+	}
+	if want, have := uint64(12), s.Drops; want != have {
+		t.Errorf("want drops %d, have %d", want, have)
+	}
+
+	s = sockets[2]
+	if want, have := net.IPv4(192, 168, 1, 1), s.RemoteAddr; !want.Equal(have) {
+		t.Errorf("want remote address %s, have %s", want, have)
+	}
+	if want, have := uint16(514), s.RemotePort; want != have {
+		t.Errorf("want remote port %d, have %d", want, have)
+	}
+	if want, have := uint64(3), s.Drops; want != have {
+		t.Errorf("want drops %d, have %d", want, have)
+	}
+	if want, have := uint64(0x100), s.TxQueue; want != have {
+		t.Errorf("want tx_queue %d, have %d", want, have)
+	}
+	if want, have := uint64(0x200), s.RxQueue; want != have {
+		t.Errorf("want rx_queue %d, have %d", want, have)
+	}
+}
+
+func TestNetUDP6(t *testing.T) {
+	sockets, err := FS("fixtures").NewNetUDP6()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 2, len(sockets); want != have {
+		t.Fatalf("want %d sockets, have %d", want, have)
+	}
+
+	s := sockets[1]
+	if want, have := net.ParseIP("fe80::1e5a:60ff:fe9a:a07b"), s.LocalAddr; !want.Equal(have) {
+		t.Errorf("want local address %s, have %s", want, have)
+	}
+	if want, have := uint16(546), s.LocalPort; want != have {
+		t.Errorf("want local port %d, have %d", want, have)
+	}
+	if want, have := uint64(5), s.Drops; want != have {
+		t.Errorf("want drops %d, have %d", want, have)
+	}
+}
+
+func TestNetUDPDropRate(t *testing.T) {
+	prev := []NetUDP{
+		{LocalPort: 53, Inode: 100, Drops: 10},
+		{LocalPort: 123, Inode: 200, Drops: 4},
+		{LocalPort: 514, Inode: 300, Drops: 7},
+	}
+	cur := []NetUDP{
+		{LocalPort: 53, Inode: 100, Drops: 25},
+		{LocalPort: 123, Inode: 200, Drops: 4},
+		// The syslog socket was recreated, its drops all happened since.
+		{LocalPort: 514, Inode: 301, Drops: 2},
+	}
+
+	drops := NetUDPDropRate(prev, cur)
+
+	for _, test := range []struct {
+		port uint16
+		want uint64
+	}{
+		{port: 53, want: 15},
+		{port: 123, want: 0},
+		{port: 514, want: 2},
+	} {
+		if have := drops[test.port]; test.want != have {
+			t.Errorf("want port %d drops %d, have %d", test.port, test.want, have)
+		}
+	}
+}
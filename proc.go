@@ -96,7 +96,9 @@ func (fs FS) AllProcs() (Procs, error) {
 	return p, nil
 }
 
-// CmdLine returns the command line of a process.
+// CmdLine returns the command line of a process. Kernel threads have an empty
+// command line. Processes which overwrote their arguments, like
+// "postgres: checkpointer", might lack the trailing NUL byte.
 func (p Proc) CmdLine() ([]string, error) {
 	f, err := os.Open(p.path("cmdline"))
 	if err != nil {
@@ -113,7 +115,7 @@ func (p Proc) CmdLine() ([]string, error) {
 		return []string{}, nil
 	}
 
-	return strings.Split(string(data[:len(data)-1]), string(byte(0))), nil
+	return strings.Split(strings.TrimSuffix(string(data), string(byte(0))), string(byte(0))), nil
 }
 
 // Comm returns the command name of a process.
@@ -162,20 +164,23 @@ func (p Proc) FileDescriptors() ([]uintptr, error) {
 }
 
 // FileDescriptorTargets returns the targets of all file descriptors of a process.
-// If a file descriptor is not a symlink to a file (like a socket), that value will be the empty string.
+// Sockets, pipes and other anonymous files have targets like "socket:[1234]".
+// File descriptors which can't be resolved, e.g. because they were closed in
+// the meantime, are skipped.
 func (p Proc) FileDescriptorTargets() ([]string, error) {
 	names, err := p.fileDescriptors()
 	if err != nil {
 		return nil, err
 	}
 
-	targets := make([]string, len(names))
+	targets := make([]string, 0, len(names))
 
-	for i, name := range names {
+	for _, name := range names {
 		target, err := os.Readlink(p.path("fd", name))
-		if err == nil {
-			targets[i] = target
+		if err != nil {
+			continue
 		}
+		targets = append(targets, target)
 	}
 
 	return targets, nil
@@ -192,6 +197,22 @@ func (p Proc) FileDescriptorsLen() (int, error) {
 	return len(fds), nil
 }
 
+// Personality returns the execution domain of a process, as read from
+// /proc/[pid]/personality. See personality(2) for the meaning of the bits.
+func (p Proc) Personality() (uint64, error) {
+	data, err := ioutil.ReadFile(p.path("personality"))
+	if err != nil {
+		return 0, err
+	}
+
+	persona, err := strconv.ParseUint(strings.TrimSpace(string(data)), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse personality %s: %s", data, err)
+	}
+
+	return persona, nil
+}
+
 // MountStats retrieves statistics and configuration for mount points in a
 // process's namespace.
 func (p Proc) MountStats() ([]*Mount, error) {
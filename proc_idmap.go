@@ -0,0 +1,88 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// IDMapRange is a single line of /proc/[pid]/uid_map or gid_map, mapping a
+// range of IDs of the process's user namespace to the parent namespace.
+type IDMapRange struct {
+	// First ID of the range inside the user namespace.
+	ContainerID uint64
+	// First ID of the range in the parent user namespace.
+	HostID uint64
+	// Number of IDs in the range.
+	Length uint64
+}
+
+// UIDMap returns the user ID mapping of the process's user namespace. The
+// result is empty if the mapping hasn't been written yet.
+func (p Proc) UIDMap() ([]IDMapRange, error) {
+	return p.idMap("uid_map")
+}
+
+// GIDMap returns the group ID mapping of the process's user namespace. The
+// result is empty if the mapping hasn't been written yet.
+func (p Proc) GIDMap() ([]IDMapRange, error) {
+	return p.idMap("gid_map")
+}
+
+func (p Proc) idMap(file string) ([]IDMapRange, error) {
+	f, err := os.Open(p.path(file))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseIDMap(f)
+}
+
+func parseIDMap(r io.Reader) ([]IDMapRange, error) {
+	var (
+		ranges  = []IDMapRange{}
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid number of fields when parsing id map: %q", scanner.Text())
+		}
+
+		var (
+			m   = IDMapRange{}
+			err error
+		)
+		if m.ContainerID, err = strconv.ParseUint(fields[0], 10, 64); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (container id): %s", fields[0], err)
+		}
+		if m.HostID, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (host id): %s", fields[1], err)
+		}
+		if m.Length, err = strconv.ParseUint(fields[2], 10, 64); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (length): %s", fields[2], err)
+		}
+
+		ranges = append(ranges, m)
+	}
+
+	return ranges, scanner.Err()
+}
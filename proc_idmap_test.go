@@ -0,0 +1,57 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIDMap(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A rootless container: root maps to the user, everything else to its
+	// subordinate IDs.
+	want := []IDMapRange{
+		{ContainerID: 0, HostID: 1000, Length: 1},
+		{ContainerID: 1, HostID: 100000, Length: 65536},
+	}
+	for name, get := range map[string]func() ([]IDMapRange, error){"uid": p.UIDMap, "gid": p.GIDMap} {
+		have, err := get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, have) {
+			t.Errorf("want %s map %v, have %v", name, want, have)
+		}
+	}
+}
+
+func TestIDMapEmpty(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26232)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := p.UIDMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m == nil || len(m) != 0 {
+		t.Errorf("want empty uid map, have %v", m)
+	}
+}
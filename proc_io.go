@@ -26,7 +26,10 @@ type ProcIO struct {
 	CancelledWriteBytes int64
 }
 
-// NewIO creates a new ProcIO instance from a given Proc instance.
+// NewIO creates a new ProcIO instance from a given Proc instance. The file is
+// only readable for processes of the same user or with ptrace access,
+// permission errors are returned as is and can be checked with
+// os.IsPermission.
 func (p Proc) NewIO() (ProcIO, error) {
 	pio := ProcIO{}
 
@@ -47,6 +50,9 @@ func (p Proc) NewIO() (ProcIO, error) {
 
 	_, err = fmt.Sscanf(string(data), ioFormat, &pio.RChar, &pio.WChar, &pio.SyscR,
 		&pio.SyscW, &pio.ReadBytes, &pio.WriteBytes, &pio.CancelledWriteBytes)
+	if err != nil {
+		return ProcIO{}, fmt.Errorf("couldn't parse io: %s", err)
+	}
 
-	return pio, err
+	return pio, nil
 }
@@ -1,6 +1,12 @@
 package procfs
 
-import "testing"
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
 
 func TestProcIO(t *testing.T) {
 	p, err := FS("fixtures").NewProc(26231)
@@ -31,3 +37,36 @@ func TestProcIO(t *testing.T) {
 		}
 	}
 }
+
+func TestProcIOErrors(t *testing.T) {
+	root, err := ioutil.TempDir("", "procfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	for pid, data := range map[int]string{1: "rchar: 750339\n", 2: "rchar: 1\n"} {
+		if err := os.MkdirAll(filepath.Join(root, fmt.Sprint(pid)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(root, fmt.Sprint(pid), "io"), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The io file of another user's process is only readable as root.
+	if err := os.Chmod(filepath.Join(root, "2", "io"), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = (Proc{PID: 1, fs: FS(root)}).NewIO()
+	if err == nil || os.IsPermission(err) {
+		t.Errorf("want parse error for truncated io, have %v", err)
+	}
+
+	if os.Geteuid() == 0 {
+		t.Skip("permissions are not enforced for root")
+	}
+	if _, err := (Proc{PID: 2, fs: FS(root)}).NewIO(); !os.IsPermission(err) {
+		t.Errorf("want permission error for unreadable io, have %v", err)
+	}
+}
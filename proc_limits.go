@@ -6,6 +6,7 @@ import (
 	"os"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 // ProcLimits represents the soft limits for each of the process's resource
@@ -66,6 +67,16 @@ var (
 
 // NewLimits returns the current soft limits of the process.
 func (p Proc) NewLimits() (ProcLimits, error) {
+	return p.newLimits(false)
+}
+
+// NewHardLimits returns the current hard limits of the process, the ceiling
+// up to which an unprivileged process may raise its soft limits.
+func (p Proc) NewHardLimits() (ProcLimits, error) {
+	return p.newLimits(true)
+}
+
+func (p Proc) newLimits(hard bool) (ProcLimits, error) {
 	f, err := os.Open(p.path("limits"))
 	if err != nil {
 		return ProcLimits{}, err
@@ -83,39 +94,46 @@ func (p Proc) NewLimits() (ProcLimits, error) {
 				"couldn't parse %s line %s", f.Name(), s.Text())
 		}
 
+		// The last field holds the hard limit followed by the optional
+		// units.
+		v := fields[1]
+		if hard {
+			v = strings.Fields(fields[2])[0]
+		}
+
 		switch fields[0] {
 		case "Max cpu time":
-			l.CPUTime, err = parseInt(fields[1])
+			l.CPUTime, err = parseInt(v)
 		case "Max file size":
-			l.FileSize, err = parseInt(fields[1])
+			l.FileSize, err = parseInt(v)
 		case "Max data size":
-			l.DataSize, err = parseInt(fields[1])
+			l.DataSize, err = parseInt(v)
 		case "Max stack size":
-			l.StackSize, err = parseInt(fields[1])
+			l.StackSize, err = parseInt(v)
 		case "Max core file size":
-			l.CoreFileSize, err = parseInt(fields[1])
+			l.CoreFileSize, err = parseInt(v)
 		case "Max resident set":
-			l.ResidentSet, err = parseInt(fields[1])
+			l.ResidentSet, err = parseInt(v)
 		case "Max processes":
-			l.Processes, err = parseInt(fields[1])
+			l.Processes, err = parseInt(v)
 		case "Max open files":
-			l.OpenFiles, err = parseInt(fields[1])
+			l.OpenFiles, err = parseInt(v)
 		case "Max locked memory":
-			l.LockedMemory, err = parseInt(fields[1])
+			l.LockedMemory, err = parseInt(v)
 		case "Max address space":
-			l.AddressSpace, err = parseInt(fields[1])
+			l.AddressSpace, err = parseInt(v)
 		case "Max file locks":
-			l.FileLocks, err = parseInt(fields[1])
+			l.FileLocks, err = parseInt(v)
 		case "Max pending signals":
-			l.PendingSignals, err = parseInt(fields[1])
+			l.PendingSignals, err = parseInt(v)
 		case "Max msgqueue size":
-			l.MsqqueueSize, err = parseInt(fields[1])
+			l.MsqqueueSize, err = parseInt(v)
 		case "Max nice priority":
-			l.NicePriority, err = parseInt(fields[1])
+			l.NicePriority, err = parseInt(v)
 		case "Max realtime priority":
-			l.RealtimePriority, err = parseInt(fields[1])
+			l.RealtimePriority, err = parseInt(v)
 		case "Max realtime timeout":
-			l.RealtimeTimeout, err = parseInt(fields[1])
+			l.RealtimeTimeout, err = parseInt(v)
 		}
 		if err != nil {
 			return ProcLimits{}, err
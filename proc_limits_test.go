@@ -29,3 +29,32 @@ func TestNewLimits(t *testing.T) {
 		}
 	}
 }
+
+func TestNewHardLimits(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := p.NewHardLimits()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name string
+		want int64
+		have int64
+	}{
+		{name: "cpu time", want: -1, have: l.CPUTime},
+		{name: "stack size", want: -1, have: l.StackSize},
+		{name: "open files", want: 4096, have: l.OpenFiles},
+		{name: "processes", want: 62898, have: l.Processes},
+		{name: "nice priority", want: 0, have: l.NicePriority},
+		{name: "address space", want: -1, have: l.AddressSpace},
+	} {
+		if test.want != test.have {
+			t.Errorf("want %s %d, have %d", test.name, test.want, test.have)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OOMCandidate is a process which might get killed by the OOM killer.
+type OOMCandidate struct {
+	PID  int
+	Comm string
+	// The badness of the process as read from /proc/[pid]/oom_score, the
+	// process with the highest score is killed first.
+	Score int
+}
+
+// OOMScore returns the current badness score of the process, which the OOM
+// killer uses to select its victim.
+func (p Proc) OOMScore() (int, error) {
+	data, err := ioutil.ReadFile(p.path("oom_score"))
+	if err != nil {
+		return 0, err
+	}
+
+	score, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("couldn't parse %s (oom_score): %s", data, err)
+	}
+
+	return score, nil
+}
+
+// TopOOMCandidates returns the n processes with the highest OOM score,
+// highest first. Processes which exit during the scan are skipped. A
+// negative n returns no processes, like 0.
+func (fs FS) TopOOMCandidates(n int) ([]OOMCandidate, error) {
+	procs, err := fs.AllProcs()
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]OOMCandidate, 0, len(procs))
+	for _, p := range procs {
+		score, err := p.OOMScore()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		comm, err := p.Comm()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		candidates = append(candidates, OOMCandidate{PID: p.PID, Comm: comm, Score: score})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].PID < candidates[j].PID
+	})
+	if n < 0 {
+		n = 0
+	}
+	if n < len(candidates) {
+		candidates = candidates[:n]
+	}
+
+	return candidates, nil
+}
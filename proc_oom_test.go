@@ -0,0 +1,53 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTopOOMCandidates(t *testing.T) {
+	// Process 584 has no oom_score, as if it exited during the scan.
+	candidates, err := FS("fixtures").TopOOMCandidates(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []OOMCandidate{
+		{PID: 26233, Comm: "postgres", Score: 812},
+		{PID: 26231, Comm: "vim", Score: 689},
+	}
+	if !reflect.DeepEqual(want, candidates) {
+		t.Errorf("want oom candidates %v, have %v", want, candidates)
+	}
+
+	candidates, err = FS("fixtures").TopOOMCandidates(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 3, len(candidates); want != have {
+		t.Errorf("want %d oom candidates, have %d", want, have)
+	}
+
+	for _, n := range []int{0, -1} {
+		candidates, err = FS("fixtures").TopOOMCandidates(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, have := 0, len(candidates); want != have {
+			t.Errorf("want %d oom candidates for n %d, have %d", want, n, have)
+		}
+	}
+}
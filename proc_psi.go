@@ -0,0 +1,143 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PSILine is a single line of a pressure stall information file.
+type PSILine struct {
+	// Percentage of wall time in which tasks were stalled, averaged over
+	// 10, 60 and 300 seconds.
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	// Total stall time in microseconds.
+	Total uint64
+}
+
+// PSIStats holds the pressure stall information of a resource, read from
+// /proc/pressure/<resource>.
+type PSIStats struct {
+	// Share of time in which at least some tasks were stalled.
+	Some *PSILine
+	// Share of time in which all non-idle tasks were stalled at once. Not
+	// available for cpu before kernel 5.13.
+	Full *PSILine
+}
+
+// NewPSIStats reads the pressure stall information of the given resource,
+// either "cpu", "memory" or "io". Requires a kernel built with
+// CONFIG_PSI, 4.20+.
+func NewPSIStats(resource string) (PSIStats, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return PSIStats{}, err
+	}
+
+	return fs.NewPSIStats(resource)
+}
+
+// NewPSIStats reads the pressure stall information of the given resource from
+// the specified `proc` filesystem.
+func (fs FS) NewPSIStats(resource string) (PSIStats, error) {
+	f, err := os.Open(fs.Path("pressure", resource))
+	if err != nil {
+		return PSIStats{}, err
+	}
+	defer f.Close()
+
+	return parsePSIStats(f)
+}
+
+// Stale reports whether the averages of s only reflect pressure which ended
+// before prev was read interval ago: the some total didn't advance in between
+// although avg10 is still elevated. The averages decay slowly, so without
+// comparing totals a past stall looks like ongoing pressure.
+func (s PSIStats) Stale(prev PSIStats, interval time.Duration) bool {
+	if s.Some == nil || prev.Some == nil || interval <= 0 {
+		return false
+	}
+
+	return s.Some.Total == prev.Some.Total && s.Some.Avg10 > 0
+}
+
+func parsePSIStats(r io.Reader) (PSIStats, error) {
+	var (
+		stats   = PSIStats{}
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		// Lines look like "some avg10=0.00 avg60=0.00 avg300=0.00 total=0".
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 5 {
+			return PSIStats{}, fmt.Errorf("invalid number of fields when parsing pressure: %q", scanner.Text())
+		}
+
+		line := &PSILine{}
+		for _, f := range fields[1:] {
+			kv := strings.SplitN(f, "=", 2)
+			if len(kv) != 2 {
+				return PSIStats{}, fmt.Errorf("malformed pressure field: %q", f)
+			}
+
+			if kv[0] == "total" {
+				v, err := strconv.ParseUint(kv[1], 10, 64)
+				if err != nil {
+					return PSIStats{}, fmt.Errorf("couldn't parse %s (total): %s", kv[1], err)
+				}
+				line.Total = v
+				continue
+			}
+
+			v, err := strconv.ParseFloat(kv[1], 64)
+			if err != nil {
+				return PSIStats{}, fmt.Errorf("couldn't parse %s (%s): %s", kv[1], kv[0], err)
+			}
+			if v < 0 || v > 100 {
+				return PSIStats{}, fmt.Errorf("%s out of range: %s", kv[0], kv[1])
+			}
+
+			switch kv[0] {
+			case "avg10":
+				line.Avg10 = v
+			case "avg60":
+				line.Avg60 = v
+			case "avg300":
+				line.Avg300 = v
+			default:
+				return PSIStats{}, fmt.Errorf("unknown pressure field: %q", kv[0])
+			}
+		}
+
+		switch fields[0] {
+		case "some":
+			stats.Some = line
+		case "full":
+			stats.Full = line
+		default:
+			return PSIStats{}, fmt.Errorf("unknown pressure line: %q", scanner.Text())
+		}
+	}
+
+	return stats, scanner.Err()
+}
@@ -0,0 +1,73 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewPSIStats(t *testing.T) {
+	cpu, err := FS("fixtures").NewPSIStats("cpu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cpu.Full != nil {
+		t.Errorf("want no full line for cpu, have %v", cpu.Full)
+	}
+	if want, have := uint64(48201932), cpu.Some.Total; want != have {
+		t.Errorf("want cpu some total %d, have %d", want, have)
+	}
+
+	memory, err := FS("fixtures").NewPSIStats("memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 1.53, memory.Some.Avg10; want != have {
+		t.Errorf("want memory some avg10 %f, have %f", want, have)
+	}
+	if want, have := 0.05, memory.Full.Avg300; want != have {
+		t.Errorf("want memory full avg300 %f, have %f", want, have)
+	}
+	if want, have := uint64(2910245), memory.Full.Total; want != have {
+		t.Errorf("want memory full total %d, have %d", want, have)
+	}
+
+	if _, err := FS("fixtures/psi/invalid").NewPSIStats("io"); err == nil {
+		t.Error("want error for avg10 above 100")
+	}
+}
+
+func TestPSIStatsStale(t *testing.T) {
+	prev, err := FS("fixtures").NewPSIStats("memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		root  string
+		stale bool
+	}{
+		{root: "fixtures/psi/advancing", stale: false},
+		{root: "fixtures/psi/stale", stale: true},
+	} {
+		cur, err := FS(tt.root).NewPSIStats("memory")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, have := tt.stale, cur.Stale(prev, 15*time.Second); want != have {
+			t.Errorf("want %s stale %t, have %t", tt.root, want, have)
+		}
+	}
+}
@@ -24,6 +24,10 @@ import (
 // - http://stackoverflow.com/questions/17410841/how-does-user-hz-solve-the-jiffy-scaling-issue
 const userHZ = 100
 
+// pfKthread is the PF_KTHREAD bit of the process flags, as defined in
+// include/linux/sched.h. It is set for kernel threads only.
+const pfKthread = 0x00200000
+
 // ProcStat provides status information about the process,
 // read from /proc/[pid]/stat.
 type ProcStat struct {
@@ -44,7 +48,8 @@ type ProcStat struct {
 	// The ID of the foreground process group of the controlling terminal of
 	// the process.
 	TPGID int
-	// The kernel flags word of the process.
+	// The kernel flags word of the process, a bitmask of the PF_* flags
+	// defined in include/linux/sched.h.
 	Flags uint
 	// The number of minor faults the process has made which have not required
 	// loading a memory page from disk.
@@ -169,6 +174,12 @@ func (s ProcStat) StartTime() (float64, error) {
 	return float64(stat.BootTime) + (float64(s.Starttime) / userHZ), nil
 }
 
+// IsKernelThread returns whether the process is a kernel thread, based on the
+// PF_KTHREAD process flag.
+func (s ProcStat) IsKernelThread() bool {
+	return s.Flags&pfKthread != 0
+}
+
 // CPUTime returns the total CPU user and system time in seconds.
 func (s ProcStat) CPUTime() float64 {
 	return float64(s.UTime+s.STime) / userHZ
@@ -100,6 +100,24 @@ func TestProcStatCPUTime(t *testing.T) {
 	}
 }
 
+func TestProcStatIsKernelThread(t *testing.T) {
+	for _, tt := range []struct {
+		process int
+		want    bool
+	}{
+		{process: 26231, want: false},
+		{process: 26232, want: true},
+	} {
+		s, err := testProcStat(tt.process)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, have := tt.want, s.IsKernelThread(); want != have {
+			t.Errorf("want process %d kernel thread %t, have %t", tt.process, want, have)
+		}
+	}
+}
+
 func testProcStat(pid int) (ProcStat, error) {
 	p, err := FS("fixtures").NewProc(pid)
 	if err != nil {
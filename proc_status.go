@@ -0,0 +1,184 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Seccomp modes as reported in the Seccomp field of /proc/[pid]/status.
+const (
+	SeccompModeDisabled = 0
+	SeccompModeStrict   = 1
+	SeccompModeFilter   = 2
+)
+
+// capabilityNames are the names of the Linux capabilities by their bit number,
+// see include/uapi/linux/capability.h.
+var capabilityNames = []string{
+	"CAP_CHOWN",
+	"CAP_DAC_OVERRIDE",
+	"CAP_DAC_READ_SEARCH",
+	"CAP_FOWNER",
+	"CAP_FSETID",
+	"CAP_KILL",
+	"CAP_SETGID",
+	"CAP_SETUID",
+	"CAP_SETPCAP",
+	"CAP_LINUX_IMMUTABLE",
+	"CAP_NET_BIND_SERVICE",
+	"CAP_NET_BROADCAST",
+	"CAP_NET_ADMIN",
+	"CAP_NET_RAW",
+	"CAP_IPC_LOCK",
+	"CAP_IPC_OWNER",
+	"CAP_SYS_MODULE",
+	"CAP_SYS_RAWIO",
+	"CAP_SYS_CHROOT",
+	"CAP_SYS_PTRACE",
+	"CAP_SYS_PACCT",
+	"CAP_SYS_ADMIN",
+	"CAP_SYS_BOOT",
+	"CAP_SYS_NICE",
+	"CAP_SYS_RESOURCE",
+	"CAP_SYS_TIME",
+	"CAP_SYS_TTY_CONFIG",
+	"CAP_MKNOD",
+	"CAP_LEASE",
+	"CAP_AUDIT_WRITE",
+	"CAP_AUDIT_CONTROL",
+	"CAP_SETFCAP",
+	"CAP_MAC_OVERRIDE",
+	"CAP_MAC_ADMIN",
+	"CAP_SYSLOG",
+	"CAP_WAKE_ALARM",
+	"CAP_BLOCK_SUSPEND",
+	"CAP_AUDIT_READ",
+	"CAP_PERFMON",
+	"CAP_BPF",
+	"CAP_CHECKPOINT_RESTORE",
+}
+
+// ProcStatus provides status information about the process, read from
+// /proc/[pid]/status.
+type ProcStatus struct {
+	// The process ID.
+	PID int
+	// The name of the process.
+	Name string
+	// Capability sets of the process as bitmasks: inheritable, permitted,
+	// effective, bounding and ambient. The ambient set is only reported
+	// by kernel 4.3+.
+	CapInh uint64
+	CapPrm uint64
+	CapEff uint64
+	CapBnd uint64
+	CapAmb uint64
+}
+
+// NewStatus returns the current status information of the process.
+func (p Proc) NewStatus() (ProcStatus, error) {
+	f, err := os.Open(p.path("status"))
+	if err != nil {
+		return ProcStatus{}, err
+	}
+	defer f.Close()
+
+	s := ProcStatus{PID: p.PID}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		kv := strings.SplitN(scanner.Text(), ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		k, v := kv[0], strings.TrimSpace(kv[1])
+
+		var field *uint64
+		switch k {
+		case "Name":
+			s.Name = v
+		case "CapInh":
+			field = &s.CapInh
+		case "CapPrm":
+			field = &s.CapPrm
+		case "CapEff":
+			field = &s.CapEff
+		case "CapBnd":
+			field = &s.CapBnd
+		case "CapAmb":
+			field = &s.CapAmb
+		}
+		if field == nil {
+			continue
+		}
+		if *field, err = parseHexUint(v); err != nil {
+			return ProcStatus{}, fmt.Errorf("couldn't parse %s (%s): %s", v, k, err)
+		}
+	}
+
+	return s, scanner.Err()
+}
+
+// EffectiveCaps returns the names of the capabilities in the effective set,
+// e.g. "CAP_NET_ADMIN", ordered by capability number. Capabilities unknown to
+// this package are named by their number, e.g. "CAP_41".
+func (s ProcStatus) EffectiveCaps() []string {
+	caps := []string{}
+	for bit := uint(0); bit < 64; bit++ {
+		if s.CapEff&(1<<bit) == 0 {
+			continue
+		}
+		if int(bit) < len(capabilityNames) {
+			caps = append(caps, capabilityNames[bit])
+		} else {
+			caps = append(caps, fmt.Sprintf("CAP_%d", bit))
+		}
+	}
+
+	return caps
+}
+
+// SeccompMode returns the seccomp mode of the process, one of the
+// SeccompMode* constants. It returns an error if the kernel was built
+// without CONFIG_SECCOMP and thus doesn't report the mode.
+func (p Proc) SeccompMode() (int, error) {
+	f, err := os.Open(p.path("status"))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) != 2 || fields[0] != "Seccomp:" {
+			continue
+		}
+
+		mode, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return 0, fmt.Errorf("couldn't parse %s (Seccomp): %s", fields[1], err)
+		}
+		return mode, nil
+	}
+	if err := s.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("no seccomp mode found in %s", f.Name())
+}
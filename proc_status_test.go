@@ -0,0 +1,68 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSeccompMode(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mode, err := p.SeccompMode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := SeccompModeFilter, mode; want != have {
+		t.Errorf("want seccomp mode %d, have %d", want, have)
+	}
+}
+
+func TestNewStatus(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := p.NewStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := ProcStatus{
+		PID:    26231,
+		Name:   "vim",
+		CapPrm: 0x1000,
+		CapEff: 0x1000,
+		CapBnd: 0x3fffffffff,
+		CapAmb: 0x1000,
+	}
+	if want != s {
+		t.Errorf("want status %+v, have %+v", want, s)
+	}
+	if want, have := []string{"CAP_NET_ADMIN"}, s.EffectiveCaps(); !reflect.DeepEqual(want, have) {
+		t.Errorf("want effective capabilities %v, have %v", want, have)
+	}
+}
+
+func TestEffectiveCaps(t *testing.T) {
+	s := ProcStatus{CapEff: 1<<21 | 1<<12 | 1<<45}
+	if want, have := []string{"CAP_NET_ADMIN", "CAP_SYS_ADMIN", "CAP_45"}, s.EffectiveCaps(); !reflect.DeepEqual(want, have) {
+		t.Errorf("want effective capabilities %v, have %v", want, have)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// ProcSyscall describes the system call a process is currently executing, as
+// read from /proc/[pid]/syscall.
+type ProcSyscall struct {
+	// Set if the process is not executing a system call. The remaining
+	// fields are only valid if Blocked is false, except for the stack
+	// pointer and program counter, which are also reported for processes
+	// blocked outside of a system call.
+	Blocked bool
+	// The system call number.
+	Number int64
+	// The argument registers of the system call.
+	Args [6]uint64
+	// The stack pointer of the process.
+	StackPointer uint64
+	// The program counter of the process.
+	ProgramCounter uint64
+}
+
+// Syscall returns the system call the process is currently executing. The
+// file is only readable with ptrace access to the process, permission errors
+// are returned as is and can be checked with os.IsPermission.
+func (p Proc) Syscall() (ProcSyscall, error) {
+	data, err := ioutil.ReadFile(p.path("syscall"))
+	if err != nil {
+		return ProcSyscall{}, err
+	}
+
+	return parseProcSyscall(string(data))
+}
+
+func parseProcSyscall(data string) (ProcSyscall, error) {
+	fields := strings.Fields(data)
+
+	// A process running in user space is reported as "running", one blocked
+	// outside of a system call as "-1 <sp> <pc>".
+	if len(fields) == 1 && fields[0] == "running" {
+		return ProcSyscall{Blocked: true}, nil
+	}
+	if len(fields) != 3 && len(fields) != 9 {
+		return ProcSyscall{}, fmt.Errorf("unexpected syscall format: %q", data)
+	}
+
+	var (
+		s   = ProcSyscall{}
+		n   = len(fields)
+		err error
+	)
+	if s.Number, err = strconv.ParseInt(fields[0], 10, 64); err != nil {
+		return ProcSyscall{}, fmt.Errorf("couldn't parse %s (syscall number): %s", fields[0], err)
+	}
+	if s.StackPointer, err = parseHexUint(fields[n-2]); err != nil {
+		return ProcSyscall{}, fmt.Errorf("couldn't parse %s (stack pointer): %s", fields[n-2], err)
+	}
+	if s.ProgramCounter, err = parseHexUint(fields[n-1]); err != nil {
+		return ProcSyscall{}, fmt.Errorf("couldn't parse %s (program counter): %s", fields[n-1], err)
+	}
+
+	if s.Number == -1 {
+		if n != 3 {
+			return ProcSyscall{}, fmt.Errorf("unexpected syscall format: %q", data)
+		}
+		s.Blocked = true
+		return s, nil
+	}
+	if n != 9 {
+		return ProcSyscall{}, fmt.Errorf("unexpected syscall format: %q", data)
+	}
+	for i := range s.Args {
+		if s.Args[i], err = parseHexUint(fields[i+1]); err != nil {
+			return ProcSyscall{}, fmt.Errorf("couldn't parse %s (argument %d): %s", fields[i+1], i, err)
+		}
+	}
+
+	return s, nil
+}
+
+// parseHexUint parses a "0x" prefixed hex number.
+func parseHexUint(s string) (uint64, error) {
+	return strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 64)
+}
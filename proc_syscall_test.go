@@ -0,0 +1,62 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProcSyscall(t *testing.T) {
+	for _, tt := range []struct {
+		process int
+		want    ProcSyscall
+	}{
+		{
+			process: 26231,
+			want: ProcSyscall{
+				Number:         7,
+				Args:           [6]uint64{0x55d1c9a6e2a0, 2, 4000, 0, 0, 0},
+				StackPointer:   0x7ffd3c9a1cf8,
+				ProgramCounter: 0x7f5c1f8e2b8f,
+			},
+		},
+		{
+			process: 26232,
+			want:    ProcSyscall{Blocked: true},
+		},
+		{
+			process: 584,
+			want: ProcSyscall{
+				Blocked:        true,
+				Number:         -1,
+				StackPointer:   0x7ffe8b9c1a38,
+				ProgramCounter: 0x55e0a3b5c1d4,
+			},
+		},
+	} {
+		p, err := FS("fixtures").NewProc(tt.process)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		have, err := p.Syscall()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tt.want, have) {
+			t.Errorf("want process %d syscall %+v, have %+v", tt.process, tt.want, have)
+		}
+	}
+}
@@ -29,9 +29,15 @@ func TestAllProcs(t *testing.T) {
 		t.Fatal(err)
 	}
 	sort.Sort(procs)
-	for i, p := range []*Proc{{PID: 584}, {PID: 26231}} {
-		if want, have := p.PID, procs[i].PID; want != have {
-			t.Errorf("want processes %d, have %d", want, have)
+
+	// Non-numeric entries like self, net or sys are no processes.
+	pids := []int{584, 26231, 26232, 26233}
+	if want, have := len(pids), len(procs); want != have {
+		t.Fatalf("want %d processes, have %d", want, have)
+	}
+	for i, pid := range pids {
+		if have := procs[i].PID; pid != have {
+			t.Errorf("want processes %d, have %d", pid, have)
 		}
 	}
 }
@@ -43,6 +49,7 @@ func TestCmdLine(t *testing.T) {
 	}{
 		{process: 26231, want: []string{"vim", "test.go", "+10"}},
 		{process: 26232, want: []string{}},
+		{process: 26233, want: []string{"postgres: checkpointer   "}},
 	} {
 		p1, err := FS("fixtures").NewProc(tt.process)
 		if err != nil {
@@ -58,6 +65,21 @@ func TestCmdLine(t *testing.T) {
 	}
 }
 
+func TestPersonality(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26231)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	persona, err := p.Personality()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := uint64(0x400000), persona; want != have {
+		t.Errorf("want personality %#x, have %#x", want, have)
+	}
+}
+
 func TestComm(t *testing.T) {
 	for _, tt := range []struct {
 		process int
@@ -139,6 +161,40 @@ func TestFileDescriptorTargets(t *testing.T) {
 	}
 }
 
+func TestFileDescriptorTargetsSkipped(t *testing.T) {
+	p, err := FS("fixtures").NewProc(26232)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := p.FileDescriptorsLen()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 8, l; want != have {
+		t.Errorf("want fds %d, have %d", want, have)
+	}
+
+	// fd 7 is not a symlink and can't be resolved.
+	targets, err := p.FileDescriptorTargets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(targets)
+	want := []string{
+		"../../symlinktargets/abc",
+		"../../symlinktargets/def",
+		"../../symlinktargets/ghi",
+		"../../symlinktargets/uvw",
+		"../../symlinktargets/xyz",
+		"pipe:[30523]",
+		"socket:[30522]",
+	}
+	if !reflect.DeepEqual(want, targets) {
+		t.Errorf("want fd targets %v, have %v", want, targets)
+	}
+}
+
 func TestFileDescriptorsLen(t *testing.T) {
 	p1, err := FS("fixtures").NewProc(26231)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // CPUStat shows how much time the cpu spend in various stages.
@@ -39,6 +40,18 @@ type SoftIRQStat struct {
 	Rcu         uint64
 }
 
+// CPUUtil holds the share of time, in percent, a CPU spent in various states
+// between two samples of /proc/stat.
+type CPUUtil struct {
+	// Time spent doing work, i.e. neither idle, waiting for IO nor stolen.
+	// Busy, Idle, Iowait and Steal add up to 100.
+	Busy   float64
+	Idle   float64
+	Iowait float64
+	// Time stolen by the hypervisor to run other guests.
+	Steal float64
+}
+
 // Stat represents kernel/system statistics.
 type Stat struct {
 	// Boot time in seconds since the Epoch.
@@ -116,6 +129,23 @@ func parseCPUStat(line string) (CPUStat, int64, error) {
 	return cpuStat, cpuID, nil
 }
 
+// ByType returns the softirq counts keyed by the softirq names used by the
+// kernel, e.g. "NET_RX".
+func (s SoftIRQStat) ByType() map[string]uint64 {
+	return map[string]uint64{
+		"HI":       s.Hi,
+		"TIMER":    s.Timer,
+		"NET_TX":   s.NetTx,
+		"NET_RX":   s.NetRx,
+		"BLOCK":    s.Block,
+		"IRQ_POLL": s.BlockIoPoll,
+		"TASKLET":  s.Tasklet,
+		"SCHED":    s.Sched,
+		"HRTIMER":  s.Hrtimer,
+		"RCU":      s.Rcu,
+	}
+}
+
 // Parse a softirq line.
 func parseSoftIRQStat(line string) (SoftIRQStat, uint64, error) {
 	softIRQStat := SoftIRQStat{}
@@ -217,3 +247,51 @@ func (fs FS) NewStat() (Stat, error) {
 
 	return stat, nil
 }
+
+// CPUUtilization returns the utilization of each CPU between the prev sample
+// and s, keyed by CPU id. CPUs which are not present in both samples, e.g.
+// because they were hotplugged in between, are skipped.
+func (s Stat) CPUUtilization(prev Stat) map[int]CPUUtil {
+	util := map[int]CPUUtil{}
+
+	for i := 0; i < len(s.CPU) && i < len(prev.CPU); i++ {
+		cur, old := s.CPU[i], prev.CPU[i]
+		// Gaps in the per-CPU slice are filled with zero values.
+		if cur == (CPUStat{}) || old == (CPUStat{}) {
+			continue
+		}
+
+		// Guest time is already accounted for in user and nice time, so it
+		// is left out here to avoid counting it twice.
+		total := (cur.User - old.User) + (cur.Nice - old.Nice) +
+			(cur.System - old.System) + (cur.Idle - old.Idle) +
+			(cur.Iowait - old.Iowait) + (cur.IRQ - old.IRQ) +
+			(cur.SoftIRQ - old.SoftIRQ) + (cur.Steal - old.Steal)
+		if total <= 0 {
+			continue
+		}
+
+		idle := cur.Idle - old.Idle
+		iowait := cur.Iowait - old.Iowait
+		steal := cur.Steal - old.Steal
+		util[i] = CPUUtil{
+			Busy:   100 * (total - idle - iowait - steal) / total,
+			Idle:   100 * idle / total,
+			Iowait: 100 * iowait / total,
+			Steal:  100 * steal / total,
+		}
+	}
+
+	return util
+}
+
+// StatInterruptRate returns the number of interrupts per second handled
+// between two samples of /proc/stat taken interval apart. It returns 0 if the
+// counter went backwards, e.g. because the system was rebooted in between.
+func StatInterruptRate(prev, cur Stat, interval time.Duration) float64 {
+	if interval <= 0 || cur.IRQTotal < prev.IRQTotal {
+		return 0
+	}
+
+	return float64(cur.IRQTotal-prev.IRQTotal) / interval.Seconds()
+}
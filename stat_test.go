@@ -1,6 +1,10 @@
 package procfs
 
-import "testing"
+import (
+	"math"
+	"testing"
+	"time"
+)
 
 func TestStat(t *testing.T) {
 	s, err := FS("fixtures").NewStat()
@@ -15,6 +19,11 @@ func TestStat(t *testing.T) {
 	if want, have := float64(31)/userHZ, s.CPU[7].SoftIRQ; want != have {
 		t.Errorf("want cpu7/softirq %v, have %v", want, have)
 	}
+	for cpu, idle := range map[int]float64{0: 1087069, 1: 1110787} {
+		if want, have := idle/userHZ, s.CPU[cpu].Idle; want != have {
+			t.Errorf("want cpu%d/idle %v, have %v", cpu, want, have)
+		}
+	}
 
 	// intr
 	if want, have := uint64(8885917), s.IRQTotal; want != have {
@@ -58,4 +67,93 @@ func TestStat(t *testing.T) {
 		t.Errorf("want softirq RCU %d, have %d", want, have)
 	}
 
+	byType := s.SoftIRQ.ByType()
+	if want, have := uint64(211099), byType["NET_RX"]; want != have {
+		t.Errorf("want softirq NET_RX %d, have %d", want, have)
+	}
+	var sum uint64
+	for _, v := range byType {
+		sum += v
+	}
+	if want, have := s.SoftIRQTotal, sum; want != have {
+		t.Errorf("want softirq types to sum up to %d, have %d", want, have)
+	}
+}
+
+func TestParseCPUStatWithoutGuest(t *testing.T) {
+	// Kernels before 2.6.24 don't report the guest columns.
+	s, cpu, err := parseCPUStat("cpu3 100 0 50 1000 3 0 2 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := int64(3), cpu; want != have {
+		t.Errorf("want cpu id %d, have %d", want, have)
+	}
+	if want, have := float64(1000)/userHZ, s.Idle; want != have {
+		t.Errorf("want idle %v, have %v", want, have)
+	}
+	if want, have := float64(1)/userHZ, s.Steal; want != have {
+		t.Errorf("want steal %v, have %v", want, have)
+	}
+	if s.Guest != 0 || s.GuestNice != 0 {
+		t.Errorf("want no guest time, have %v and %v", s.Guest, s.GuestNice)
+	}
+}
+
+func TestCPUUtilization(t *testing.T) {
+	prev, err := FS("fixtures/cpuutil/prev").NewStat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cur, err := FS("fixtures/cpuutil/cur").NewStat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	util := cur.CPUUtilization(prev)
+
+	if want, have := 2, len(util); want != have {
+		t.Fatalf("want utilization for %d cpus, have %d: %v", want, have, util)
+	}
+	for _, cpu := range []int{2, 3} {
+		if _, ok := util[cpu]; ok {
+			t.Errorf("want hotplugged cpu%d to be skipped", cpu)
+		}
+	}
+
+	for _, test := range []struct {
+		name string
+		want float64
+		have float64
+	}{
+		{name: "cpu0 busy", want: 70, have: util[0].Busy},
+		{name: "cpu0 idle", want: 25, have: util[0].Idle},
+		{name: "cpu0 iowait", want: 5, have: util[0].Iowait},
+		{name: "cpu1 busy", want: 15, have: util[1].Busy},
+		{name: "cpu1 idle", want: 80, have: util[1].Idle},
+		{name: "cpu1 steal", want: 5, have: util[1].Steal},
+	} {
+		if math.Abs(test.want-test.have) > 1e-9 {
+			t.Errorf("want %s %v, have %v", test.name, test.want, test.have)
+		}
+	}
+}
+
+func TestStatInterruptRate(t *testing.T) {
+	prev, err := FS("fixtures/cpuutil/prev").NewStat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cur, err := FS("fixtures/cpuutil/cur").NewStat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 100.0, StatInterruptRate(prev, cur, 10*time.Second); want != have {
+		t.Errorf("want interrupt rate %f, have %f", want, have)
+	}
+	if want, have := 0.0, StatInterruptRate(cur, prev, 10*time.Second); want != have {
+		t.Errorf("want interrupt rate %f for reset counter, have %f", want, have)
+	}
 }
@@ -0,0 +1,172 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/prometheus/procfs"
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// BlockDevice contains info about a block device, as exposed in
+// /sys/block/<dev>.
+type BlockDevice struct {
+	// Name of the device, e.g. "sda".
+	Name string
+	// Size of the device in bytes.
+	SizeBytes uint64
+	// Whether the device has removable media.
+	Removable bool
+	// I/O statistics of the device, in the same format as a line of
+	// /proc/diskstats.
+	Stat procfs.DiskStat
+}
+
+// BlockQueue contains the request queue settings of a block device, as
+// exposed in /sys/block/<dev>/queue.
+type BlockQueue struct {
+	// Active I/O scheduler, e.g. "mq-deadline" or "none".
+	Scheduler string
+	// All I/O schedulers available for the device.
+	AvailableSchedulers []string
+	// Maximum number of requests in the queue.
+	NRRequests int64
+	// Read ahead size in KiB.
+	ReadAheadKB int64
+	// Whether the device is a rotational disk rather than an SSD.
+	Rotational bool
+	// Smallest unit the device can address in bytes.
+	LogicalBlockSize int64
+	// Smallest unit the device can write without read-modify-write in bytes.
+	PhysicalBlockSize int64
+	// Maximum size of a request in KiB.
+	MaxSectorsKB int64
+}
+
+// NewBlockDevice returns info about the block device dev, e.g. "sda".
+func (fs FS) NewBlockDevice(dev string) (BlockDevice, error) {
+	var (
+		path = fs.Path("block", dev)
+		d    = BlockDevice{Name: dev}
+	)
+
+	size, err := readSysfsInt(filepath.Join(path, "size"))
+	if err != nil {
+		return BlockDevice{}, err
+	}
+	d.SizeBytes = uint64(size) * sectorSize
+
+	removable, err := readSysfsInt(filepath.Join(path, "removable"))
+	if err != nil {
+		return BlockDevice{}, err
+	}
+	d.Removable = removable == 1
+
+	if d.Stat, err = parseBlockStat(path); err != nil {
+		return BlockDevice{}, err
+	}
+
+	return d, nil
+}
+
+// NewBlockDeviceQueue returns the request queue settings of the block device
+// dev, e.g. "sda".
+func (fs FS) NewBlockDeviceQueue(dev string) (BlockQueue, error) {
+	path := fs.Path("block", dev, "queue")
+
+	scheduler, err := readSysfsString(filepath.Join(path, "scheduler"))
+	if err != nil {
+		return BlockQueue{}, err
+	}
+	q := BlockQueue{}
+	q.Scheduler, q.AvailableSchedulers = parseBlockScheduler(scheduler)
+
+	var rotational int64
+	for _, f := range []struct {
+		name string
+		v    *int64
+	}{
+		{name: "nr_requests", v: &q.NRRequests},
+		{name: "read_ahead_kb", v: &q.ReadAheadKB},
+		{name: "rotational", v: &rotational},
+		{name: "logical_block_size", v: &q.LogicalBlockSize},
+		{name: "physical_block_size", v: &q.PhysicalBlockSize},
+		{name: "max_sectors_kb", v: &q.MaxSectorsKB},
+	} {
+		if *f.v, err = readSysfsInt(filepath.Join(path, f.name)); err != nil {
+			return BlockQueue{}, err
+		}
+	}
+	q.Rotational = rotational == 1
+
+	return q, nil
+}
+
+// parseBlockScheduler parses the content of the scheduler file, which lists
+// all available schedulers with the active one in brackets, e.g.
+// "[mq-deadline] kyber none". Devices without a scheduler only list "none".
+func parseBlockScheduler(s string) (active string, available []string) {
+	for _, f := range strings.Fields(s) {
+		if strings.HasPrefix(f, "[") && strings.HasSuffix(f, "]") {
+			f = strings.Trim(f, "[]")
+			active = f
+		}
+		available = append(available, f)
+	}
+	if active == "" && len(available) == 1 {
+		active = available[0]
+	}
+
+	return active, available
+}
+
+func parseBlockStat(path string) (procfs.DiskStat, error) {
+	s := procfs.DiskStat{Name: filepath.Base(path)}
+
+	dev, err := readSysfsString(filepath.Join(path, "dev"))
+	if err != nil {
+		return procfs.DiskStat{}, err
+	}
+	if _, err := fmt.Sscanf(dev, "%d:%d", &s.Major, &s.Minor); err != nil {
+		return procfs.DiskStat{}, fmt.Errorf("couldn't parse %s (dev): %s", dev, err)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(path, "stat"))
+	if err != nil {
+		return procfs.DiskStat{}, err
+	}
+	// The file holds the counters of a /proc/diskstats line without the
+	// device numbers and name, 11 fields before kernel 4.18, 15 before 5.5.
+	fields := strings.Fields(string(data))
+	if len(fields) != 11 && len(fields) != 15 && len(fields) != 17 {
+		return procfs.DiskStat{}, fmt.Errorf("invalid number of fields when parsing %s stat: %q", s.Name, data)
+	}
+
+	counters := []*uint64{
+		&s.ReadIOs, &s.ReadMerges, &s.ReadSectors, &s.ReadTicks,
+		&s.WriteIOs, &s.WriteMerges, &s.WriteSectors, &s.WriteTicks,
+		&s.IOsInProgress, &s.IOTicks, &s.TimeInQueue,
+		&s.DiscardIOs, &s.DiscardMerges, &s.DiscardSectors, &s.DiscardTicks,
+		&s.FlushIOs, &s.FlushTicks,
+	}
+	if err := util.ParseCounters(s.Name, fields, counters); err != nil {
+		return procfs.DiskStat{}, err
+	}
+
+	return s, nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/procfs"
+)
+
+func TestNewBlockDevice(t *testing.T) {
+	for _, tt := range []struct {
+		dev       string
+		sizeBytes uint64
+		readIOs   uint64
+		flushIOs  uint64
+	}{
+		{dev: "sda", sizeBytes: 976773168 * 512, readIOs: 93843},
+		{dev: "nvme0n1", sizeBytes: 1000215216 * 512, readIOs: 1134583, flushIOs: 41022},
+	} {
+		d, err := FS("fixtures").NewBlockDevice(tt.dev)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want, have := tt.sizeBytes, d.SizeBytes; want != have {
+			t.Errorf("want %s size %d, have %d", tt.dev, want, have)
+		}
+		if d.Removable {
+			t.Errorf("want %s not removable", tt.dev)
+		}
+		if want, have := tt.readIOs, d.Stat.ReadIOs; want != have {
+			t.Errorf("want %s read ios %d, have %d", tt.dev, want, have)
+		}
+		if want, have := tt.flushIOs, d.Stat.FlushIOs; want != have {
+			t.Errorf("want %s flush ios %d, have %d", tt.dev, want, have)
+		}
+	}
+
+	d, err := FS("fixtures").NewBlockDevice("sda")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := procfs.DiskStat{
+		Major:        8,
+		Name:         "sda",
+		ReadIOs:      93843,
+		ReadMerges:   8120,
+		ReadSectors:  9600000,
+		ReadTicks:    72520,
+		WriteIOs:     240120,
+		WriteMerges:  190043,
+		WriteSectors: 14400000,
+		WriteTicks:   1310312,
+		IOTicks:      351024,
+		TimeInQueue:  1350000,
+	}
+	if !reflect.DeepEqual(want, d.Stat) {
+		t.Errorf("want sda stat %+v, have %+v", want, d.Stat)
+	}
+}
+
+func TestNewBlockDeviceQueue(t *testing.T) {
+	q, err := FS("fixtures").NewBlockDeviceQueue("sda")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := BlockQueue{
+		Scheduler:           "mq-deadline",
+		AvailableSchedulers: []string{"mq-deadline", "kyber", "bfq", "none"},
+		NRRequests:          64,
+		ReadAheadKB:         128,
+		Rotational:          true,
+		LogicalBlockSize:    512,
+		PhysicalBlockSize:   4096,
+		MaxSectorsKB:        1280,
+	}
+	if !reflect.DeepEqual(want, q) {
+		t.Errorf("want block queue %+v, have %+v", want, q)
+	}
+}
+
+func TestParseBlockScheduler(t *testing.T) {
+	for _, tt := range []struct {
+		in     string
+		active string
+	}{
+		{in: "[none] mq-deadline", active: "none"},
+		{in: "noop deadline [cfq]", active: "cfq"},
+		{in: "none", active: "none"},
+	} {
+		if have, _ := parseBlockScheduler(tt.in); tt.active != have {
+			t.Errorf("want active scheduler %q of %q, have %q", tt.active, tt.in, have)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// CgroupIOStat contains the block IO accounting of a cgroup v2 for a single
+// device, as read from io.stat.
+type CgroupIOStat struct {
+	// Bytes read.
+	RBytes uint64
+	// Bytes written.
+	WBytes uint64
+	// Number of read IOs.
+	RIOs uint64
+	// Number of write IOs.
+	WIOs uint64
+	// Bytes discarded.
+	DBytes uint64
+	// Number of discard IOs.
+	DIOs uint64
+}
+
+// NewCgroupIOStat reads the io.stat file of the cgroup at path, relative to
+// /sys/fs/cgroup, keyed by the "major:minor" number of the device. Devices
+// without any IO are not listed by the kernel.
+func (fs FS) NewCgroupIOStat(path string) (map[string]CgroupIOStat, error) {
+	f, err := os.Open(fs.Path("fs/cgroup", path, "io.stat"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseCgroupIOStat(f)
+}
+
+func parseCgroupIOStat(r io.Reader) (map[string]CgroupIOStat, error) {
+	var (
+		stats   = map[string]CgroupIOStat{}
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		// Lines look like "8:16 rbytes=1459200 wbytes=314773504 rios=192
+		// wios=353 dbytes=0 dios=0".
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		s := CgroupIOStat{}
+		for _, f := range fields[1:] {
+			kv := strings.SplitN(f, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("malformed io.stat field %q of device %s", f, fields[0])
+			}
+			// Newer kernels append keys like "cost.vrate=100.00" or
+			// "depth=max", which aren't plain counters, so only the known
+			// keys are parsed.
+			var p *uint64
+			switch kv[0] {
+			case "rbytes":
+				p = &s.RBytes
+			case "wbytes":
+				p = &s.WBytes
+			case "rios":
+				p = &s.RIOs
+			case "wios":
+				p = &s.WIOs
+			case "dbytes":
+				p = &s.DBytes
+			case "dios":
+				p = &s.DIOs
+			default:
+				continue
+			}
+
+			v, err := strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse %s (%s): %s", kv[1], kv[0], err)
+			}
+			*p = v
+		}
+		stats[fields[0]] = s
+	}
+
+	return stats, scanner.Err()
+}
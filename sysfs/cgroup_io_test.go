@@ -0,0 +1,33 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import "testing"
+
+func TestNewCgroupIOStat(t *testing.T) {
+	stats, err := FS("fixtures").NewCgroupIOStat("system.slice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 2, len(stats); want != have {
+		t.Fatalf("want %d devices, have %d", want, have)
+	}
+	if want, have := uint64(314773504), stats["8:16"].WBytes; want != have {
+		t.Errorf("want 8:16 wbytes %d, have %d", want, have)
+	}
+	if want, have := uint64(1), stats["253:0"].DIOs; want != have {
+		t.Errorf("want 253:0 dios %d, have %d", want, have)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var hwmonInputRE = regexp.MustCompile(`^(temp|fan|in)\d+_input$`)
+
+// HWMon contains the sensor readings of a single hardware monitoring chip, read
+// from /sys/class/hwmon/hwmon<N>. The sensor maps are keyed by the sensor's
+// name like "temp1", as labels aren't unique.
+type HWMon struct {
+	// Name of the hwmon directory, e.g. "hwmon0".
+	Name string
+	// Name of the chip, e.g. "coretemp".
+	Chip string
+	// Temperature sensors, scaled to degrees Celsius.
+	Temps map[string]HWMonSensor
+	// Fan sensors in RPM.
+	Fans map[string]HWMonSensor
+	// Voltage sensors, scaled to volts.
+	Voltages map[string]HWMonSensor
+}
+
+// HWMonSensor is the reading of a single hwmon sensor.
+type HWMonSensor struct {
+	// Label of the sensor as provided by the driver, e.g. "Package id 0".
+	// Empty if the driver provides none.
+	Label string
+	// Value as read from the <sensor>_input file, in millidegrees Celsius
+	// for temperatures, RPM for fans and millivolts for voltages.
+	Raw int64
+	// Value in the base unit of the sensor.
+	Value float64
+}
+
+// NewHWMon returns the readings of all hwmon chips read from /sys/class/hwmon,
+// ordered by the number of their directory.
+func (fs FS) NewHWMon() ([]HWMon, error) {
+	matches, err := filepath.Glob(fs.Path("class/hwmon/hwmon[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+	// Sort hwmon10 after hwmon2.
+	sort.Slice(matches, func(i, j int) bool {
+		a, _ := strconv.Atoi(strings.TrimPrefix(filepath.Base(matches[i]), "hwmon"))
+		b, _ := strconv.Atoi(strings.TrimPrefix(filepath.Base(matches[j]), "hwmon"))
+		return a < b
+	})
+
+	chips := make([]HWMon, 0, len(matches))
+	for _, m := range matches {
+		h, err := parseHWMon(m)
+		if err != nil {
+			return nil, err
+		}
+		chips = append(chips, h)
+	}
+
+	return chips, nil
+}
+
+func parseHWMon(path string) (HWMon, error) {
+	h := HWMon{
+		Name:     filepath.Base(path),
+		Temps:    map[string]HWMonSensor{},
+		Fans:     map[string]HWMonSensor{},
+		Voltages: map[string]HWMonSensor{},
+	}
+
+	// Some drivers, e.g. on older kernels, put the attributes into the
+	// device directory instead.
+	chip, err := readSysfsString(filepath.Join(path, "name"))
+	if os.IsNotExist(err) {
+		path = filepath.Join(path, "device")
+		chip, err = readSysfsString(filepath.Join(path, "name"))
+	}
+	if err != nil {
+		return HWMon{}, err
+	}
+	h.Chip = chip
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return HWMon{}, err
+	}
+	for _, f := range files {
+		m := hwmonInputRE.FindStringSubmatch(f.Name())
+		if m == nil {
+			continue
+		}
+
+		raw, err := readSysfsInt(filepath.Join(path, f.Name()))
+		if err != nil {
+			return HWMon{}, err
+		}
+
+		sensor := strings.TrimSuffix(f.Name(), "_input")
+		label, err := readSysfsString(filepath.Join(path, sensor+"_label"))
+		if err != nil && !os.IsNotExist(err) {
+			return HWMon{}, err
+		}
+
+		switch m[1] {
+		case "temp":
+			h.Temps[sensor] = HWMonSensor{Label: label, Raw: raw, Value: float64(raw) / 1000}
+		case "fan":
+			h.Fans[sensor] = HWMonSensor{Label: label, Raw: raw, Value: float64(raw)}
+		case "in":
+			h.Voltages[sensor] = HWMonSensor{Label: label, Raw: raw, Value: float64(raw) / 1000}
+		}
+	}
+
+	return h, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewHWMon(t *testing.T) {
+	chips, err := FS("fixtures").NewHWMon()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// hwmon10 is ordered after hwmon2.
+	var names []string
+	for _, c := range chips {
+		names = append(names, c.Name)
+	}
+	if want, have := []string{"hwmon0", "hwmon1", "hwmon2", "hwmon10"}, names; !reflect.DeepEqual(want, have) {
+		t.Fatalf("want hwmon chips %v, have %v", want, have)
+	}
+
+	coretemp := chips[0]
+	if want, have := "coretemp", coretemp.Chip; want != have {
+		t.Errorf("want chip %q, have %q", want, have)
+	}
+	if want, have := (HWMonSensor{Label: "Package id 0", Raw: 45000, Value: 45}), coretemp.Temps["temp1"]; want != have {
+		t.Errorf("want package temperature %+v, have %+v", want, have)
+	}
+	if want, have := 2, len(coretemp.Temps); want != have {
+		t.Errorf("want %d coretemp temperatures, have %d", want, have)
+	}
+
+	// The nct6775 driver reports the same label for several inputs.
+	nct := chips[1]
+	for _, tt := range []struct {
+		name    string
+		sensors map[string]HWMonSensor
+		sensor  string
+		want    HWMonSensor
+	}{
+		{name: "temperature", sensors: nct.Temps, sensor: "temp1", want: HWMonSensor{Raw: 38500, Value: 38.5}},
+		{name: "temperature", sensors: nct.Temps, sensor: "temp2", want: HWMonSensor{Label: "AUXTIN0", Raw: 29000, Value: 29}},
+		{name: "temperature", sensors: nct.Temps, sensor: "temp3", want: HWMonSensor{Label: "AUXTIN0", Raw: 31000, Value: 31}},
+		{name: "fan", sensors: nct.Fans, sensor: "fan1", want: HWMonSensor{Raw: 1200, Value: 1200}},
+		{name: "voltage", sensors: nct.Voltages, sensor: "in0", want: HWMonSensor{Label: "Vcore", Raw: 1032, Value: 1.032}},
+	} {
+		if have := tt.sensors[tt.sensor]; tt.want != have {
+			t.Errorf("want %s %s %+v, have %+v", tt.name, tt.sensor, tt.want, have)
+		}
+	}
+}
@@ -0,0 +1,552 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/procfs/internal/util"
+)
+
+// Files which may hold the firmware version of a network device. There is no
+// common attribute, the few drivers exposing it in sysfs use one of these.
+var netClassFirmwareFiles = []string{"fw_version", "firmware_version", "fw_ver"}
+
+// Names of the offload features, as used by ethtool, by their bit position in
+// the netdev_features_t bitmask, see include/linux/netdev_features.h. The
+// positions are kept across kernel versions, removed features leave their bit
+// unused. Bits 12 and 13 held NETIF_F_LLTX and NETIF_F_NETNS_LOCAL up to Linux
+// 6.11 and aren't decoded, as they are unused since.
+var netClassFeatureBits = []string{
+	0:  "tx-scatter-gather",
+	1:  "tx-checksum-ipv4",
+	3:  "tx-checksum-ip-generic",
+	4:  "tx-checksum-ipv6",
+	5:  "highdma",
+	6:  "tx-scatter-gather-fraglist",
+	7:  "tx-vlan-hw-insert",
+	8:  "rx-vlan-hw-parse",
+	9:  "rx-vlan-filter",
+	10: "vlan-challenged",
+	11: "tx-generic-segmentation",
+	14: "rx-gro",
+	15: "rx-lro",
+	16: "tx-tcp-segmentation",
+	17: "tx-gso-robust",
+	18: "tx-tcp-ecn-segmentation",
+	19: "tx-tcp-mangleid-segmentation",
+	20: "tx-tcp6-segmentation",
+}
+
+// Link speeds in Mbit/s of the ethtool link modes by their bit position in the
+// supported link modes bitmask, see ethtool_link_mode_bit_indices in
+// include/uapi/linux/ethtool.h. Bits which don't denote a speed are omitted.
+var netClassLinkModeSpeeds = map[uint]int64{
+	0: 10, 1: 10,
+	2: 100, 3: 100,
+	4: 1000, 5: 1000, 17: 1000, 41: 1000,
+	15: 2500, 47: 2500,
+	48: 5000,
+	12: 10000, 18: 10000, 19: 10000, 20: 10000, 42: 10000, 43: 10000, 44: 10000, 45: 10000, 46: 10000,
+	21: 20000, 22: 20000,
+	31: 25000, 32: 25000, 33: 25000,
+	23: 40000, 24: 40000, 25: 40000, 26: 40000,
+	34: 50000, 35: 50000, 40: 50000,
+	27: 56000, 28: 56000, 29: 56000, 30: 56000,
+	36: 100000, 37: 100000, 38: 100000, 39: 100000,
+	52: 50000, 53: 50000, 54: 50000, 55: 50000, 56: 50000,
+	57: 100000, 58: 100000, 59: 100000, 60: 100000, 61: 100000,
+	62: 200000, 63: 200000, 64: 200000, 65: 200000, 66: 200000,
+	67: 100, 68: 1000,
+	69: 400000, 70: 400000, 71: 400000, 72: 400000, 73: 400000,
+	75: 100000, 76: 100000, 77: 100000, 78: 100000, 79: 100000,
+	80: 200000, 81: 200000, 82: 200000, 83: 200000, 84: 200000,
+	85: 400000, 86: 400000, 87: 400000, 88: 400000, 89: 400000,
+	90: 100, 91: 100,
+}
+
+// NetClassIface contains info from files in /sys/class/net/<iface> for a
+// single interface.
+type NetClassIface struct {
+	// Name of the interface.
+	Name string
+	// Name of the kernel driver bound to the underlying device. Empty for
+	// virtual interfaces.
+	Driver string
+	// Firmware version of the underlying device, if exposed by the driver.
+	FirmwareVersion string
+	// Hardware address of the interface.
+	Address string
+	// RFC 2863 operational state, e.g. "up", "down" or "unknown".
+	OperState string
+	// Whether the physical link is up. Only meaningful while the
+	// interface is administratively up.
+	Carrier bool
+	// Link speed in Mbit/s, 0 if unknown, e.g. for virtual interfaces or
+	// interfaces without link.
+	Speed int64
+	// Duplex mode of the link, "full", "half" or "unknown". Empty if the
+	// interface doesn't report it.
+	Duplex string
+	// Highest link speed in Mbit/s of the link modes the interface supports,
+	// which is available even while the link is down. 0 if the interface
+	// doesn't expose its supported link modes.
+	MaxSupportedSpeed int64
+	// Maximum transmission unit of the interface in bytes.
+	MTU int64
+	// Name of the bridge or bond the interface is enslaved to, if any.
+	Master string
+	// Whether VLAN filtering is enabled. Only set for bridge interfaces.
+	BridgeVLANFiltering bool
+	// Port VLAN id of a bridge port, 0 if the interface is no bridge port or
+	// the kernel doesn't expose it.
+	BridgePortPVID int64
+	// NUMA node of the underlying device, -1 if it has no NUMA affinity or
+	// the interface is virtual.
+	NUMANode int
+	// Kind of the interface, e.g. "vlan", "vxlan", "tun", "bond" or
+	// "bridge". Physical interfaces can have a kind as well, like "wlan"
+	// for wireless devices. Empty for plain Ethernet devices and interfaces
+	// without a recognizable kind, like veth.
+	Kind string
+	// Counters of the statistics directory, e.g. "rx_bytes", keyed by file
+	// name.
+	Statistics map[string]uint64
+	// Offload features by their ethtool name, e.g. "rx-gro", decoded from
+	// the features bitmask. Nil if the interface doesn't expose it.
+	Features map[string]bool
+}
+
+// NetStatRate holds the per second rates of an interface's traffic counters.
+type NetStatRate struct {
+	RxBytes  float64
+	TxBytes  float64
+	RxErrors float64
+	TxErrors float64
+}
+
+// RPSCoverage summarizes the receive packet steering (RPS) configuration of
+// the receive queues of an interface.
+type RPSCoverage struct {
+	// Number of receive queues.
+	Queues int
+	// Number of distinct CPUs enabled for RPS on any receive queue.
+	CoveredCPUs int
+	// Whether RPS is disabled on all queues although the interface has
+	// multiple of them.
+	Disabled bool
+}
+
+// NewNetClass returns info for all net interfaces read from
+// /sys/class/net/<iface>, keyed by interface name.
+func (fs FS) NewNetClass() (map[string]NetClassIface, error) {
+	path := fs.Path("class/net")
+
+	devices, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	netClass := make(map[string]NetClassIface, len(devices))
+	for _, d := range devices {
+		// Interfaces are symlinks to their device directory. Skip regular
+		// files like bonding_masters, which exists while the bonding module
+		// is loaded.
+		fi, err := os.Stat(filepath.Join(path, d.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			continue
+		}
+
+		iface, err := parseNetClassIface(filepath.Join(path, d.Name()))
+		if err != nil {
+			return nil, err
+		}
+		netClass[iface.Name] = iface
+	}
+
+	return netClass, nil
+}
+
+// MTUMismatch returns the interfaces whose MTU differs from the MTU of their
+// bridge or bond master, mapped to their own MTU. Such mismatches usually
+// lead to silently dropped packets.
+func (fs FS) MTUMismatch() (map[string]int, error) {
+	netClass, err := fs.NewNetClass()
+	if err != nil {
+		return nil, err
+	}
+
+	mismatch := map[string]int{}
+	for name, iface := range netClass {
+		if iface.Master == "" {
+			continue
+		}
+		master, ok := netClass[iface.Master]
+		if !ok {
+			return nil, fmt.Errorf("master %s of interface %s not found", iface.Master, name)
+		}
+		if iface.MTU != master.MTU {
+			mismatch[name] = int(iface.MTU)
+		}
+	}
+
+	return mismatch, nil
+}
+
+// NetClassStatsRate computes the per second rates of the traffic counters of
+// all interfaces present in both samples. Unlike /proc/net/dev, the sysfs
+// statistics are available for every interface. Counters which wrapped at 32
+// bits, as they do on 32-bit kernels, are accounted for. Counters which went
+// backwards otherwise are assumed to have been reset.
+func NetClassStatsRate(prev, cur map[string]NetClassIface, interval time.Duration) map[string]NetStatRate {
+	rates := map[string]NetStatRate{}
+	if interval <= 0 {
+		return rates
+	}
+
+	secs := interval.Seconds()
+	rate := func(p, c NetClassIface, name string) float64 {
+		return float64(util.CounterDelta(p.Statistics[name], c.Statistics[name])) / secs
+	}
+	for name, c := range cur {
+		p, ok := prev[name]
+		if !ok {
+			continue
+		}
+		rates[name] = NetStatRate{
+			RxBytes:  rate(p, c, "rx_bytes"),
+			TxBytes:  rate(p, c, "tx_bytes"),
+			RxErrors: rate(p, c, "rx_errors"),
+			TxErrors: rate(p, c, "tx_errors"),
+		}
+	}
+
+	return rates
+}
+
+// NewRSSTable returns the RSS indirection table of the interface iface, i.e.
+// the receive queue index of each hash bucket, for drivers which expose it in
+// device/rss_indir_table. This isn't a standard sysfs attribute, the kernel
+// itself only reports the table via the ETHTOOL_GRXFHINDIR ioctl. For drivers
+// which don't add the file, the returned error can be checked with
+// os.IsNotExist.
+func (fs FS) NewRSSTable(iface string) ([]int, error) {
+	data, err := readSysfsString(fs.Path("class/net", iface, "device/rss_indir_table"))
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Fields(data)
+	table := make([]int, len(fields))
+	for i, f := range fields {
+		if table[i], err = strconv.Atoi(f); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (rss_indir_table): %s", f, err)
+		}
+	}
+
+	return table, nil
+}
+
+// RPSCoverage reads the rps_cpus masks of all receive queues of the interface
+// iface.
+func (fs FS) RPSCoverage(iface string) (RPSCoverage, error) {
+	matches, err := filepath.Glob(fs.Path("class/net", iface, "queues/rx-*/rps_cpus"))
+	if err != nil {
+		return RPSCoverage{}, err
+	}
+	if len(matches) == 0 {
+		return RPSCoverage{}, fmt.Errorf("no receive queues found for %s", iface)
+	}
+
+	covered := map[int]bool{}
+	for _, m := range matches {
+		mask, err := readSysfsString(m)
+		if err != nil {
+			return RPSCoverage{}, err
+		}
+		cpus, err := parseCPUMask(mask)
+		if err != nil {
+			return RPSCoverage{}, err
+		}
+		for _, cpu := range cpus {
+			covered[cpu] = true
+		}
+	}
+
+	return RPSCoverage{
+		Queues:      len(matches),
+		CoveredCPUs: len(covered),
+		Disabled:    len(covered) == 0 && len(matches) > 1,
+	}, nil
+}
+
+// parseCPUMask returns the CPUs set in a hex CPU mask like "00000000,0000000f",
+// as printed by the kernel for cpumask attributes.
+func parseCPUMask(mask string) ([]int, error) {
+	var (
+		cpus []int
+		hex  = strings.Replace(mask, ",", "", -1)
+	)
+
+	for i := len(hex) - 1; i >= 0; i-- {
+		nibble, err := strconv.ParseUint(hex[i:i+1], 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (cpu mask): %s", mask, err)
+		}
+		for bit := uint(0); bit < 4; bit++ {
+			if nibble&(1<<bit) != 0 {
+				cpus = append(cpus, (len(hex)-1-i)*4+int(bit))
+			}
+		}
+	}
+
+	return cpus, nil
+}
+
+func parseNetClassIface(path string) (NetClassIface, error) {
+	iface := NetClassIface{Name: filepath.Base(path), NUMANode: -1}
+
+	driver, err := os.Readlink(filepath.Join(path, "device/driver"))
+	switch {
+	case err == nil:
+		iface.Driver = filepath.Base(driver)
+	case !os.IsNotExist(err):
+		return NetClassIface{}, err
+	}
+
+	master, err := os.Readlink(filepath.Join(path, "master"))
+	switch {
+	case err == nil:
+		iface.Master = filepath.Base(master)
+	case !os.IsNotExist(err):
+		return NetClassIface{}, err
+	}
+
+	if iface.MTU, err = readSysfsInt(filepath.Join(path, "mtu")); err != nil {
+		return NetClassIface{}, err
+	}
+
+	// Link attributes are missing or fail to read with EINVAL for interfaces
+	// which don't support them or are down.
+	if iface.Address, err = readNetClassAttr(filepath.Join(path, "address")); err != nil {
+		return NetClassIface{}, err
+	}
+	if iface.OperState, err = readNetClassAttr(filepath.Join(path, "operstate")); err != nil {
+		return NetClassIface{}, err
+	}
+	if iface.Duplex, err = readNetClassAttr(filepath.Join(path, "duplex")); err != nil {
+		return NetClassIface{}, err
+	}
+	carrier, err := readNetClassAttr(filepath.Join(path, "carrier"))
+	if err != nil {
+		return NetClassIface{}, err
+	}
+	iface.Carrier = carrier == "1"
+	speed, err := readNetClassAttr(filepath.Join(path, "speed"))
+	if err != nil {
+		return NetClassIface{}, err
+	}
+	// Virtual interfaces report -1 (SPEED_UNKNOWN) or garbage.
+	if v, err := strconv.ParseInt(speed, 10, 64); err == nil && v > 0 {
+		iface.Speed = v
+	}
+
+	filtering, err := readSysfsInt(filepath.Join(path, "bridge/vlan_filtering"))
+	switch {
+	case err == nil:
+		iface.BridgeVLANFiltering = filtering == 1
+	case !os.IsNotExist(err):
+		return NetClassIface{}, err
+	}
+
+	pvid, err := readSysfsInt(filepath.Join(path, "brport/pvid"))
+	switch {
+	case err == nil:
+		iface.BridgePortPVID = pvid
+	case !os.IsNotExist(err):
+		return NetClassIface{}, err
+	}
+
+	if iface.Statistics, err = parseNetClassStatistics(filepath.Join(path, "statistics")); err != nil {
+		return NetClassIface{}, err
+	}
+
+	supported, err := readNetClassAttr(filepath.Join(path, "supported"))
+	if err != nil {
+		return NetClassIface{}, err
+	}
+	if supported != "" {
+		if iface.MaxSupportedSpeed, err = parseNetClassMaxSpeed(supported); err != nil {
+			return NetClassIface{}, err
+		}
+	}
+
+	features, err := readSysfsString(filepath.Join(path, "features"))
+	switch {
+	case err == nil:
+		if iface.Features, err = parseNetClassFeatures(features); err != nil {
+			return NetClassIface{}, err
+		}
+	case !os.IsNotExist(err):
+		return NetClassIface{}, err
+	}
+
+	if iface.Kind, err = parseNetClassKind(path); err != nil {
+		return NetClassIface{}, err
+	}
+
+	node, err := readSysfsInt(filepath.Join(path, "device/numa_node"))
+	switch {
+	case err == nil:
+		iface.NUMANode = int(node)
+	case !os.IsNotExist(err):
+		return NetClassIface{}, err
+	}
+
+	for _, f := range netClassFirmwareFiles {
+		fw, err := readSysfsString(filepath.Join(path, "device", f))
+		if err == nil {
+			iface.FirmwareVersion = fw
+			break
+		}
+		if !os.IsNotExist(err) {
+			return NetClassIface{}, err
+		}
+	}
+
+	return iface, nil
+}
+
+// parseNetClassFeatures decodes a features bitmask like "0x0000000000114833".
+func parseNetClassFeatures(s string) (map[string]bool, error) {
+	mask, err := strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 64)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse %s (features): %s", s, err)
+	}
+
+	features := make(map[string]bool, len(netClassFeatureBits))
+	for bit, name := range netClassFeatureBits {
+		if name == "" {
+			continue
+		}
+		features[name] = mask&(1<<uint(bit)) != 0
+	}
+
+	return features, nil
+}
+
+// parseNetClassMaxSpeed returns the highest speed of the link modes in a
+// supported link modes bitmask like "0x00000000000010e8". The mask is wider
+// than 64 bits for interfaces supporting the link modes of 200G and up.
+func parseNetClassMaxSpeed(s string) (int64, error) {
+	mask, ok := new(big.Int).SetString(strings.TrimPrefix(s, "0x"), 16)
+	if !ok {
+		return 0, fmt.Errorf("couldn't parse %s (supported)", s)
+	}
+
+	var max int64
+	for bit, speed := range netClassLinkModeSpeeds {
+		if mask.Bit(int(bit)) != 0 && speed > max {
+			max = speed
+		}
+	}
+
+	return max, nil
+}
+
+// readNetClassAttr reads an optional network interface attribute, returning an
+// empty string if the attribute is missing or not supported by the interface.
+func readNetClassAttr(path string) (string, error) {
+	s, err := readSysfsString(path)
+	switch {
+	case err == nil:
+		return s, nil
+	case os.IsNotExist(err):
+		return "", nil
+	}
+	if pe, ok := err.(*os.PathError); ok && pe.Err == syscall.EINVAL {
+		return "", nil
+	}
+
+	return "", err
+}
+
+func parseNetClassStatistics(path string) (map[string]uint64, error) {
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	stats := make(map[string]uint64, len(files))
+	for _, f := range files {
+		v, err := readSysfsString(filepath.Join(path, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if stats[f.Name()], err = strconv.ParseUint(v, 10, 64); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (%s): %s", v, f.Name(), err)
+		}
+	}
+
+	return stats, nil
+}
+
+// parseNetClassKind infers the kind of an interface. Most drivers set
+// DEVTYPE in the uevent, the remaining kinds are recognized by their
+// type-specific files. Other interfaces, e.g. physical ones or veth, have an
+// empty kind.
+func parseNetClassKind(path string) (string, error) {
+	uevent, err := readSysfsString(filepath.Join(path, "uevent"))
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	for _, line := range strings.Split(uevent, "\n") {
+		if strings.HasPrefix(line, "DEVTYPE=") {
+			return strings.TrimPrefix(line, "DEVTYPE="), nil
+		}
+	}
+
+	for _, k := range []struct {
+		file string
+		kind string
+	}{
+		{file: "bridge", kind: "bridge"},
+		{file: "bonding", kind: "bond"},
+		{file: "tun_flags", kind: "tun"},
+	} {
+		_, err := os.Stat(filepath.Join(path, k.file))
+		if err == nil {
+			return k.kind, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// bondModeLACP is the name of the IEEE 802.3ad dynamic link aggregation mode.
+const bondModeLACP = "802.3ad"
+
+// NetBond contains info from files in /sys/class/net/<bond>/bonding for a
+// single bonding interface.
+type NetBond struct {
+	// Name of the bonding interface.
+	Name string
+	// Bonding mode, e.g. "active-backup" or "802.3ad".
+	Mode string
+	// Rate at which LACPDUs are requested from the partner, "slow" or
+	// "fast". Only set for 802.3ad bonds.
+	LACPRate string
+	// Slave interfaces of the bond.
+	Slaves []NetBondSlave
+}
+
+// NetBondSlave contains info from files in
+// /sys/class/net/<slave>/bonding_slave for a single slave of a bond.
+type NetBondSlave struct {
+	// Name of the slave interface.
+	Name string
+	// MII link status of the slave, "up" or "down".
+	MIIStatus string
+	// Id of the 802.3ad aggregator the slave belongs to. Only set for
+	// 802.3ad bonds.
+	AggregatorID int64
+	// Operational state of the LACP actor as a bitmask, see IEEE 802.1AX.
+	// Only set for 802.3ad bonds.
+	ActorOperPortState int64
+}
+
+// NewNetBonds returns info for all bonding interfaces read from
+// /sys/class/net.
+func (fs FS) NewNetBonds() ([]NetBond, error) {
+	matches, err := filepath.Glob(fs.Path("class/net/*/bonding"))
+	if err != nil {
+		return nil, err
+	}
+
+	bonds := make([]NetBond, 0, len(matches))
+	for _, m := range matches {
+		b, err := parseNetBond(m)
+		if err != nil {
+			return nil, err
+		}
+		bonds = append(bonds, b)
+	}
+
+	return bonds, nil
+}
+
+func parseNetBond(path string) (NetBond, error) {
+	bond := NetBond{Name: filepath.Base(filepath.Dir(path))}
+
+	// Mode and LACP rate are printed with their numeric value, e.g.
+	// "802.3ad 4".
+	mode, err := readSysfsString(filepath.Join(path, "mode"))
+	if err != nil {
+		return NetBond{}, err
+	}
+	bond.Mode = firstField(mode)
+	lacp := bond.Mode == bondModeLACP
+
+	if lacp {
+		rate, err := readSysfsString(filepath.Join(path, "lacp_rate"))
+		if err != nil {
+			return NetBond{}, err
+		}
+		bond.LACPRate = firstField(rate)
+	}
+
+	slaves, err := readSysfsString(filepath.Join(path, "slaves"))
+	if err != nil {
+		return NetBond{}, err
+	}
+	for _, name := range strings.Fields(slaves) {
+		slave, err := parseNetBondSlave(filepath.Join(path, "../..", name, "bonding_slave"), lacp)
+		if err != nil {
+			return NetBond{}, err
+		}
+		slave.Name = name
+		bond.Slaves = append(bond.Slaves, slave)
+	}
+
+	return bond, nil
+}
+
+func parseNetBondSlave(path string, lacp bool) (NetBondSlave, error) {
+	var (
+		slave = NetBondSlave{}
+		err   error
+	)
+
+	if slave.MIIStatus, err = readSysfsString(filepath.Join(path, "mii_status")); err != nil {
+		return NetBondSlave{}, err
+	}
+	if !lacp {
+		return slave, nil
+	}
+
+	if slave.AggregatorID, err = readSysfsInt(filepath.Join(path, "ad_aggregator_id")); err != nil {
+		return NetBondSlave{}, err
+	}
+	if slave.ActorOperPortState, err = readSysfsInt(filepath.Join(path, "ad_actor_oper_port_state")); err != nil {
+		return NetBondSlave{}, err
+	}
+
+	return slave, nil
+}
+
+func firstField(s string) string {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
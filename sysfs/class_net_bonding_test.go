@@ -0,0 +1,49 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewNetBonds(t *testing.T) {
+	bonds, err := FS("fixtures").NewNetBonds()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []NetBond{
+		{
+			Name:     "bond0",
+			Mode:     "802.3ad",
+			LACPRate: "fast",
+			Slaves: []NetBondSlave{
+				{Name: "eth1", MIIStatus: "up", AggregatorID: 1, ActorOperPortState: 61},
+				{Name: "eth2", MIIStatus: "up", AggregatorID: 2, ActorOperPortState: 69},
+			},
+		},
+		{
+			Name: "bond1",
+			Mode: "active-backup",
+			Slaves: []NetBondSlave{
+				{Name: "eth3", MIIStatus: "down"},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(want, bonds) {
+		t.Errorf("want bonds %+v, have %+v", want, bonds)
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewNetClass(t *testing.T) {
+	netClass, err := FS("fixtures").NewNetClass()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := netClass["bonding_masters"]; ok {
+		t.Error("want bonding_masters to be skipped")
+	}
+
+	eth0, ok := netClass["eth0"]
+	if !ok {
+		t.Fatal("want interface eth0 to be present")
+	}
+	if want, have := "e1000e", eth0.Driver; want != have {
+		t.Errorf("want eth0 driver %q, have %q", want, have)
+	}
+	if want, have := "0.13-4", eth0.FirmwareVersion; want != have {
+		t.Errorf("want eth0 firmware version %q, have %q", want, have)
+	}
+	if want, have := int64(1500), eth0.MTU; want != have {
+		t.Errorf("want eth0 mtu %d, have %d", want, have)
+	}
+	if want, have := "68:05:ca:1f:3b:a4", eth0.Address; want != have {
+		t.Errorf("want eth0 address %q, have %q", want, have)
+	}
+	if want, have := "up", eth0.OperState; want != have {
+		t.Errorf("want eth0 operstate %q, have %q", want, have)
+	}
+	if !eth0.Carrier {
+		t.Error("want eth0 carrier to be up")
+	}
+	if want, have := int64(1000), eth0.Speed; want != have {
+		t.Errorf("want eth0 speed %d, have %d", want, have)
+	}
+	if want, have := "full", eth0.Duplex; want != have {
+		t.Errorf("want eth0 duplex %q, have %q", want, have)
+	}
+	for feature, enabled := range map[string]bool{
+		"rx-gro":               true,
+		"tx-tcp6-segmentation": true,
+		"rx-lro":               false,
+	} {
+		if want, have := enabled, eth0.Features[feature]; want != have {
+			t.Errorf("want eth0 feature %s %t, have %t", feature, want, have)
+		}
+	}
+	if _, ok := eth0.Features["tx-lockless"]; ok {
+		t.Error("want unused feature bit tx-lockless to be skipped")
+	}
+	eth3 := netClass["eth3"]
+	if want, have := int64(0), eth3.Speed; want != have {
+		t.Errorf("want eth3 speed %d, have %d", want, have)
+	}
+	if want, have := int64(10000), eth3.MaxSupportedSpeed; want != have {
+		t.Errorf("want eth3 max supported speed %d, have %d", want, have)
+	}
+	if want, have := int64(0), eth0.MaxSupportedSpeed; want != have {
+		t.Errorf("want eth0 max supported speed %d, have %d", want, have)
+	}
+	// The supported link modes of eth2 include 200G modes above bit 63.
+	if want, have := int64(200000), netClass["eth2"].MaxSupportedSpeed; want != have {
+		t.Errorf("want eth2 max supported speed %d, have %d", want, have)
+	}
+	if want, have := int64(0), netClass["bond1"].Speed; want != have {
+		t.Errorf("want bond1 speed %d, have %d", want, have)
+	}
+	if want, have := uint64(68210035552), eth0.Statistics["rx_bytes"]; want != have {
+		t.Errorf("want eth0 rx_bytes %d, have %d", want, have)
+	}
+	if want, have := "bond0", netClass["eth1"].Master; want != have {
+		t.Errorf("want eth1 master %q, have %q", want, have)
+	}
+	if !netClass["br0"].BridgeVLANFiltering {
+		t.Error("want br0 vlan filtering to be enabled")
+	}
+	if eth0.BridgeVLANFiltering {
+		t.Error("want no vlan filtering for eth0")
+	}
+	if want, have := int64(10), netClass["veth0"].BridgePortPVID; want != have {
+		t.Errorf("want veth0 pvid %d, have %d", want, have)
+	}
+	for name, kind := range map[string]string{
+		"eth0":     "",
+		"eth0.100": "vlan",
+		"veth0":    "",
+		"br0":      "bridge",
+		"bond0":    "bond",
+	} {
+		if want, have := kind, netClass[name].Kind; want != have {
+			t.Errorf("want %s kind %q, have %q", name, want, have)
+		}
+	}
+
+	lo, ok := netClass["lo"]
+	if !ok {
+		t.Fatal("want interface lo to be present")
+	}
+	if want, have := 1, eth0.NUMANode; want != have {
+		t.Errorf("want eth0 numa node %d, have %d", want, have)
+	}
+	if lo.Features != nil {
+		t.Errorf("want no features for lo, have %v", lo.Features)
+	}
+	if want, have := "unknown", lo.OperState; want != have {
+		t.Errorf("want lo operstate %q, have %q", want, have)
+	}
+	if want, have := int64(65536), lo.MTU; want != have {
+		t.Errorf("want lo mtu %d, have %d", want, have)
+	}
+	if want, have := -1, lo.NUMANode; want != have {
+		t.Errorf("want lo numa node %d, have %d", want, have)
+	}
+	if lo.Driver != "" || lo.FirmwareVersion != "" {
+		t.Errorf("want no driver and firmware for lo, have %q and %q", lo.Driver, lo.FirmwareVersion)
+	}
+}
+
+func TestMTUMismatch(t *testing.T) {
+	mismatch, err := FS("fixtures").MTUMismatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// eth2 is a slave of bond0, which uses jumbo frames.
+	if want, have := map[string]int{"eth2": 1500}, mismatch; !reflect.DeepEqual(want, have) {
+		t.Errorf("want mtu mismatches %v, have %v", want, have)
+	}
+}
+
+func TestNetClassStatsRate(t *testing.T) {
+	cur, err := FS("fixtures").NewNetClass()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prev := map[string]NetClassIface{
+		"eth0": {Statistics: map[string]uint64{
+			"rx_bytes": 68209035552,
+			// Wrapped at 32 bits on a 32-bit kernel.
+			"tx_bytes":  4294746688,
+			"rx_errors": 3,
+			// Reset by reloading the driver in the meantime.
+			"tx_errors": 2,
+		}},
+	}
+	rates := NetClassStatsRate(prev, cur, 10*time.Second)
+
+	if want, have := 1, len(rates); want != have {
+		t.Fatalf("want rates for %d interfaces, have %d", want, have)
+	}
+	for _, tt := range []struct {
+		name string
+		want float64
+		have float64
+	}{
+		{name: "rx bytes", want: 100000, have: rates["eth0"].RxBytes},
+		{name: "tx bytes", want: 26500000, have: rates["eth0"].TxBytes},
+		{name: "rx errors", want: 0.4, have: rates["eth0"].RxErrors},
+		{name: "tx errors", want: 0, have: rates["eth0"].TxErrors},
+	} {
+		if tt.want != tt.have {
+			t.Errorf("want eth0 %s rate %f, have %f", tt.name, tt.want, tt.have)
+		}
+	}
+}
+
+func TestNewRSSTable(t *testing.T) {
+	table, err := FS("fixtures").NewRSSTable("eth0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 16, len(table); want != have {
+		t.Fatalf("want %d hash buckets, have %d", want, have)
+	}
+	for _, bucket := range []int{1, 6, 15} {
+		if want, have := bucket%4, table[bucket]; want != have {
+			t.Errorf("want bucket %d queue %d, have %d", bucket, want, have)
+		}
+	}
+
+	if _, err := FS("fixtures").NewRSSTable("lo"); !os.IsNotExist(err) {
+		t.Errorf("want not exist error for interface without rss indirection table, have %v", err)
+	}
+}
+
+func TestRPSCoverage(t *testing.T) {
+	for _, tt := range []struct {
+		iface string
+		want  RPSCoverage
+	}{
+		{iface: "eth0", want: RPSCoverage{Queues: 4, CoveredCPUs: 6}},
+		{iface: "eth1", want: RPSCoverage{Queues: 2, Disabled: true}},
+	} {
+		have, err := FS("fixtures").RPSCoverage(tt.iface)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.want != have {
+			t.Errorf("want %s rps coverage %+v, have %+v", tt.iface, tt.want, have)
+		}
+	}
+
+	if _, err := FS("fixtures").RPSCoverage("lo"); err == nil {
+		t.Error("want error for interface without receive queues")
+	}
+}
+
+func TestParseCPUMask(t *testing.T) {
+	cpus, err := parseCPUMask("00000001,00000090")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := []int{4, 7, 32}, cpus; !reflect.DeepEqual(want, have) {
+		t.Errorf("want cpus %v, have %v", want, have)
+	}
+}
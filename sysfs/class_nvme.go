@@ -0,0 +1,110 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"path/filepath"
+)
+
+// sectorSize is the unit in which the kernel reports block device sizes in
+// sysfs, regardless of the device's logical block size.
+const sectorSize = 512
+
+// NVMeDevice contains info from files in /sys/class/nvme/<device> for a
+// single NVMe controller.
+type NVMeDevice struct {
+	// Name of the controller, e.g. "nvme0".
+	Name string
+	// Serial number of the controller.
+	Serial string
+	// Model of the controller.
+	Model string
+	// Firmware revision of the controller.
+	FirmwareRevision string
+	// Controller state, e.g. "live".
+	State string
+	// NUMA node the controller is attached to, -1 if there is no affinity.
+	NUMANode int64
+	// Namespaces of the controller.
+	Namespaces []NVMeNamespace
+}
+
+// NVMeNamespace contains info from files in /sys/class/nvme/<device>/<ns>
+// for a single namespace of an NVMe controller.
+type NVMeNamespace struct {
+	// Name of the namespace block device, e.g. "nvme0n1".
+	Name string
+	// Size of the namespace in bytes.
+	SizeBytes uint64
+}
+
+// NewNVMeDevices returns info for all NVMe controllers read from
+// /sys/class/nvme.
+func (fs FS) NewNVMeDevices() ([]NVMeDevice, error) {
+	matches, err := filepath.Glob(fs.Path("class/nvme/nvme[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]NVMeDevice, 0, len(matches))
+	for _, m := range matches {
+		d, err := parseNVMeDevice(m)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, d)
+	}
+
+	return devices, nil
+}
+
+func parseNVMeDevice(path string) (NVMeDevice, error) {
+	var (
+		d   = NVMeDevice{Name: filepath.Base(path)}
+		err error
+	)
+
+	if d.Serial, err = readSysfsString(filepath.Join(path, "serial")); err != nil {
+		return NVMeDevice{}, err
+	}
+	if d.Model, err = readSysfsString(filepath.Join(path, "model")); err != nil {
+		return NVMeDevice{}, err
+	}
+	if d.FirmwareRevision, err = readSysfsString(filepath.Join(path, "firmware_rev")); err != nil {
+		return NVMeDevice{}, err
+	}
+	if d.State, err = readSysfsString(filepath.Join(path, "state")); err != nil {
+		return NVMeDevice{}, err
+	}
+	if d.NUMANode, err = readSysfsInt(filepath.Join(path, "numa_node")); err != nil {
+		return NVMeDevice{}, err
+	}
+
+	matches, err := filepath.Glob(filepath.Join(path, d.Name+"n[0-9]*"))
+	if err != nil {
+		return NVMeDevice{}, err
+	}
+	for _, m := range matches {
+		sectors, err := readSysfsInt(filepath.Join(m, "size"))
+		if err != nil {
+			return NVMeDevice{}, err
+		}
+		d.Namespaces = append(d.Namespaces, NVMeNamespace{
+			Name:      filepath.Base(m),
+			SizeBytes: uint64(sectors) * sectorSize,
+		})
+	}
+
+	return d, nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import "testing"
+
+func TestNewNVMeDevices(t *testing.T) {
+	devices, err := FS("fixtures").NewNVMeDevices()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 1, len(devices); want != have {
+		t.Fatalf("want %d nvme devices, have %d", want, have)
+	}
+
+	d := devices[0]
+	if want, have := "nvme0", d.Name; want != have {
+		t.Errorf("want device name %q, have %q", want, have)
+	}
+	if want, have := "Samsung SSD 970 EVO Plus 1TB", d.Model; want != have {
+		t.Errorf("want model %q, have %q", want, have)
+	}
+	if want, have := "live", d.State; want != have {
+		t.Errorf("want state %q, have %q", want, have)
+	}
+	if want, have := int64(0), d.NUMANode; want != have {
+		t.Errorf("want numa node %d, have %d", want, have)
+	}
+
+	if want, have := 1, len(d.Namespaces); want != have {
+		t.Fatalf("want %d namespaces, have %d", want, have)
+	}
+	if want, have := "nvme0n1", d.Namespaces[0].Name; want != have {
+		t.Errorf("want namespace name %q, have %q", want, have)
+	}
+	if want, have := uint64(1000204886016), d.Namespaces[0].SizeBytes; want != have {
+		t.Errorf("want namespace size %d, have %d", want, have)
+	}
+}
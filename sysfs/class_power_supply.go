@@ -0,0 +1,107 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// PowerSupply contains info from files in /sys/class/power_supply/<name> for
+// a single battery, AC adapter or other power supply. Which attributes are
+// exposed depends on the type of the supply and the driver, missing ones are
+// left zero.
+type PowerSupply struct {
+	// Name of the power supply, e.g. "BAT0" or "AC".
+	Name string
+	// Type of the power supply, e.g. "Battery", "Mains" or "USB".
+	Type string
+	// Charging status of a battery, e.g. "Charging" or "Discharging".
+	Status string
+	// Remaining capacity of a battery in percent.
+	Capacity int64
+	// Energy stored in a battery in µWh.
+	EnergyNow int64
+	// Energy stored in a fully charged battery in µWh.
+	EnergyFull int64
+	// Current voltage in µV.
+	VoltageNow int64
+	// Whether an AC adapter or USB supply is connected.
+	Online bool
+}
+
+// NewPowerSupplies returns info for all power supplies read from
+// /sys/class/power_supply.
+func (fs FS) NewPowerSupplies() ([]PowerSupply, error) {
+	path := fs.Path("class/power_supply")
+
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	supplies := make([]PowerSupply, 0, len(entries))
+	for _, e := range entries {
+		s, err := parsePowerSupply(filepath.Join(path, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		supplies = append(supplies, s)
+	}
+
+	return supplies, nil
+}
+
+func parsePowerSupply(path string) (PowerSupply, error) {
+	var (
+		s   = PowerSupply{Name: filepath.Base(path)}
+		err error
+	)
+
+	if s.Type, err = readSysfsString(filepath.Join(path, "type")); err != nil {
+		return PowerSupply{}, err
+	}
+
+	status, err := readSysfsString(filepath.Join(path, "status"))
+	switch {
+	case err == nil:
+		s.Status = status
+	case !os.IsNotExist(err):
+		return PowerSupply{}, err
+	}
+
+	var online int64
+	for _, f := range []struct {
+		name string
+		v    *int64
+	}{
+		{name: "capacity", v: &s.Capacity},
+		{name: "energy_now", v: &s.EnergyNow},
+		{name: "energy_full", v: &s.EnergyFull},
+		{name: "voltage_now", v: &s.VoltageNow},
+		{name: "online", v: &online},
+	} {
+		v, err := readSysfsInt(filepath.Join(path, f.name))
+		switch {
+		case err == nil:
+			*f.v = v
+		case !os.IsNotExist(err):
+			return PowerSupply{}, err
+		}
+	}
+	s.Online = online == 1
+
+	return s, nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewPowerSupplies(t *testing.T) {
+	supplies, err := FS("fixtures").NewPowerSupplies()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []PowerSupply{
+		{Name: "AC", Type: "Mains", Online: true},
+		{
+			Name:       "BAT0",
+			Type:       "Battery",
+			Status:     "Discharging",
+			Capacity:   84,
+			EnergyNow:  42310000,
+			EnergyFull: 50370000,
+			VoltageNow: 12283000,
+		},
+	}
+	if !reflect.DeepEqual(want, supplies) {
+		t.Errorf("want power supplies %+v, have %+v", want, supplies)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RAPLZone contains info from files in /sys/class/powercap/intel-rapl:<N>
+// for a single Intel RAPL (Running Average Power Limit) power zone.
+type RAPLZone struct {
+	// Name of the zone directory, e.g. "intel-rapl:0:1". Subzones are
+	// named after their parent zone.
+	Zone string
+	// Name of the power domain, e.g. "package-0", "core" or "dram".
+	Name string
+	// Energy consumed in µJ. The counter wraps around to 0 after
+	// MaxEnergyRangeUJ.
+	EnergyUJ int64
+	// Range of the energy counter in µJ.
+	MaxEnergyRangeUJ int64
+	// Power limits of the zone, ordered by constraint number.
+	Constraints []RAPLConstraint
+}
+
+// RAPLConstraint is a power limit of a RAPL zone.
+type RAPLConstraint struct {
+	// Name of the constraint, e.g. "long_term" or "short_term".
+	Name string
+	// Power limit in µW, 0 if disabled.
+	PowerLimitUW int64
+	// Time window in µs over which the power limit is averaged.
+	TimeWindowUS int64
+}
+
+// NewRAPL returns info for all RAPL zones and subzones read from
+// /sys/class/powercap. Reading the energy counters requires root privileges
+// on kernel 5.10+.
+func (fs FS) NewRAPL() ([]RAPLZone, error) {
+	matches, err := filepath.Glob(fs.Path("class/powercap/intel-rapl:*"))
+	if err != nil {
+		return nil, err
+	}
+
+	zones := make([]RAPLZone, 0, len(matches))
+	for _, m := range matches {
+		z, err := parseRAPLZone(m)
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, z)
+	}
+
+	return zones, nil
+}
+
+// RAPLPower computes the average power in watts of all zones present in both
+// samples taken interval apart, keyed by zone directory name. Energy counters
+// which wrapped around at MaxEnergyRangeUJ are accounted for.
+func RAPLPower(prev, cur []RAPLZone, interval time.Duration) map[string]float64 {
+	power := map[string]float64{}
+	if interval <= 0 {
+		return power
+	}
+
+	previous := make(map[string]RAPLZone, len(prev))
+	for _, p := range prev {
+		previous[p.Zone] = p
+	}
+
+	for _, c := range cur {
+		p, ok := previous[c.Zone]
+		if !ok {
+			continue
+		}
+
+		delta := c.EnergyUJ - p.EnergyUJ
+		if delta < 0 {
+			delta += c.MaxEnergyRangeUJ
+		}
+		power[c.Zone] = float64(delta) / 1e6 / interval.Seconds()
+	}
+
+	return power
+}
+
+func parseRAPLZone(path string) (RAPLZone, error) {
+	var (
+		z   = RAPLZone{Zone: filepath.Base(path)}
+		err error
+	)
+
+	if z.Name, err = readSysfsString(filepath.Join(path, "name")); err != nil {
+		return RAPLZone{}, err
+	}
+	if z.EnergyUJ, err = readSysfsInt(filepath.Join(path, "energy_uj")); err != nil {
+		return RAPLZone{}, err
+	}
+	if z.MaxEnergyRangeUJ, err = readSysfsInt(filepath.Join(path, "max_energy_range_uj")); err != nil {
+		return RAPLZone{}, err
+	}
+
+	for i := 0; ; i++ {
+		prefix := filepath.Join(path, fmt.Sprintf("constraint_%d_", i))
+
+		name, err := readSysfsString(prefix + "name")
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return RAPLZone{}, err
+		}
+
+		c := RAPLConstraint{Name: name}
+		if c.PowerLimitUW, err = readSysfsInt(prefix + "power_limit_uw"); err != nil {
+			return RAPLZone{}, err
+		}
+		if c.TimeWindowUS, err = readSysfsInt(prefix + "time_window_us"); err != nil {
+			return RAPLZone{}, err
+		}
+		z.Constraints = append(z.Constraints, c)
+	}
+
+	return z, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewRAPL(t *testing.T) {
+	zones, err := FS("fixtures").NewRAPL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 3, len(zones); want != have {
+		t.Fatalf("want %d rapl zones, have %d", want, have)
+	}
+
+	pkg := RAPLZone{
+		Zone:             "intel-rapl:0",
+		Name:             "package-0",
+		EnergyUJ:         240422366267,
+		MaxEnergyRangeUJ: 262143328850,
+		Constraints: []RAPLConstraint{
+			{Name: "long_term", PowerLimitUW: 95000000, TimeWindowUS: 27983872},
+			{Name: "short_term", PowerLimitUW: 118750000, TimeWindowUS: 2440},
+		},
+	}
+	if have := zones[0]; !reflect.DeepEqual(pkg, have) {
+		t.Errorf("want package zone %+v, have %+v", pkg, have)
+	}
+
+	dram := zones[2]
+	if want, have := "dram", dram.Name; want != have {
+		t.Errorf("want zone name %q, have %q", want, have)
+	}
+	if want, have := int64(24468775213), dram.EnergyUJ; want != have {
+		t.Errorf("want dram energy %d, have %d", want, have)
+	}
+	if want, have := int64(65712999613), dram.MaxEnergyRangeUJ; want != have {
+		t.Errorf("want dram max energy range %d, have %d", want, have)
+	}
+}
+
+func TestRAPLPower(t *testing.T) {
+	prev, err := FS("fixtures").NewRAPL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cur := make([]RAPLZone, len(prev))
+	copy(cur, prev)
+	// The package counter wrapped around after 10 J, followed by 440 J.
+	cur[0].EnergyUJ = 440000000
+	prev[0].EnergyUJ = prev[0].MaxEnergyRangeUJ - 10000000
+	// A zone missing from the previous sample has no power.
+	prev = prev[:2]
+
+	power := RAPLPower(prev, cur, 10*time.Second)
+
+	if want, have := 45.0, power["intel-rapl:0"]; want != have {
+		t.Errorf("want package power %f W, have %f W", want, have)
+	}
+	if want, have := 0.0, power["intel-rapl:0:0"]; want != have {
+		t.Errorf("want core power %f W, have %f W", want, have)
+	}
+	if _, ok := power["intel-rapl:0:1"]; ok {
+		t.Errorf("want no power for zone intel-rapl:0:1")
+	}
+}
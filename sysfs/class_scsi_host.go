@@ -0,0 +1,93 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// SCSIHost contains info from files in /sys/class/scsi_host/host<N> for a
+// single SCSI host bus adapter. Attributes not exposed by the driver are
+// left zero.
+type SCSIHost struct {
+	// Name of the host, e.g. "host0".
+	Name string
+	// State of the host, e.g. "running" or "recovery".
+	State string
+	// Maximum number of commands the host can have outstanding.
+	CanQueue int64
+	// Maximum number of commands per logical unit.
+	CmdPerLUN int64
+	// Name of the driver, e.g. "ahci" or "megaraid_sas".
+	ProcName string
+}
+
+// NewSCSIHosts returns info for all SCSI hosts read from
+// /sys/class/scsi_host.
+func (fs FS) NewSCSIHosts() ([]SCSIHost, error) {
+	matches, err := filepath.Glob(fs.Path("class/scsi_host/host*"))
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make([]SCSIHost, 0, len(matches))
+	for _, m := range matches {
+		h, err := parseSCSIHost(m)
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, h)
+	}
+
+	return hosts, nil
+}
+
+func parseSCSIHost(path string) (SCSIHost, error) {
+	h := SCSIHost{Name: filepath.Base(path)}
+
+	for _, f := range []struct {
+		name string
+		v    *string
+	}{
+		{name: "state", v: &h.State},
+		{name: "proc_name", v: &h.ProcName},
+	} {
+		v, err := readSysfsString(filepath.Join(path, f.name))
+		switch {
+		case err == nil:
+			*f.v = v
+		case !os.IsNotExist(err):
+			return SCSIHost{}, err
+		}
+	}
+
+	for _, f := range []struct {
+		name string
+		v    *int64
+	}{
+		{name: "can_queue", v: &h.CanQueue},
+		{name: "cmd_per_lun", v: &h.CmdPerLUN},
+	} {
+		v, err := readSysfsInt(filepath.Join(path, f.name))
+		switch {
+		case err == nil:
+			*f.v = v
+		case !os.IsNotExist(err):
+			return SCSIHost{}, err
+		}
+	}
+
+	return h, nil
+}
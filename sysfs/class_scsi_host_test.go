@@ -0,0 +1,35 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewSCSIHosts(t *testing.T) {
+	hosts, err := FS("fixtures").NewSCSIHosts()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []SCSIHost{
+		// host0 doesn't expose its state.
+		{Name: "host0", CanQueue: 32, CmdPerLUN: 1, ProcName: "ahci"},
+		{Name: "host1", State: "running", CanQueue: 8000, CmdPerLUN: 254, ProcName: "megaraid_sas"},
+	}
+	if !reflect.DeepEqual(want, hosts) {
+		t.Errorf("want scsi hosts %+v, have %+v", want, hosts)
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	thermalZoneCdevRE = regexp.MustCompile(`^cdev\d+$`)
+	thermalZoneTripRE = regexp.MustCompile(`^trip_point_(\d+)_type$`)
+)
+
+// CoolingDevice contains info from files in
+// /sys/class/thermal/cooling_device<N> for a single cooling device.
+type CoolingDevice struct {
+	// Name of the cooling device directory, e.g. "cooling_device0".
+	Name string
+	// Type of the cooling device, e.g. "Fan" or "Processor".
+	Type string
+	// Current cooling state, between 0 and MaxState.
+	CurState int64
+	// Maximum cooling state of the device.
+	MaxState int64
+}
+
+// ThermalZone contains info from files in /sys/class/thermal/thermal_zone<N>
+// for a single thermal zone.
+type ThermalZone struct {
+	// Name of the thermal zone directory, e.g. "thermal_zone0".
+	Name string
+	// Type of the thermal zone, e.g. "acpitz" or "x86_pkg_temp".
+	Type string
+	// Current temperature in millidegrees Celsius.
+	Temp int64
+	// Thermal governor of the zone, e.g. "step_wise".
+	Policy string
+	// Trip points of the zone, ordered by their number. Empty for zones
+	// without trip points.
+	TripPoints []ThermalZoneTripPoint
+	// Cooling devices bound to the trip points of the zone.
+	CoolingDevices []ThermalZoneCoolingDevice
+}
+
+// ThermalZoneTripPoint is a temperature threshold of a thermal zone.
+type ThermalZoneTripPoint struct {
+	// Type of the trip point, e.g. "passive", "active" or "critical".
+	Type string
+	// Temperature of the trip point in millidegrees Celsius.
+	Temp int64
+}
+
+// ThermalZoneCoolingDevice describes the binding of a cooling device to a
+// trip point of a thermal zone.
+type ThermalZoneCoolingDevice struct {
+	// Name of the bound cooling device, e.g. "cooling_device0".
+	Device string
+	// Trip point of the zone at which the cooling device is activated.
+	TripPoint int64
+}
+
+// NewCoolingDevices returns info for all cooling devices read from
+// /sys/class/thermal, ordered by the number of their directory.
+func (fs FS) NewCoolingDevices() ([]CoolingDevice, error) {
+	matches, err := filepath.Glob(fs.Path("class/thermal/cooling_device[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+	sortThermalPaths(matches, "cooling_device")
+
+	devices := make([]CoolingDevice, 0, len(matches))
+	for _, m := range matches {
+		d, err := parseCoolingDevice(m)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, d)
+	}
+
+	return devices, nil
+}
+
+// NewThermalZones returns info for all thermal zones read from
+// /sys/class/thermal, ordered by the number of their directory.
+func (fs FS) NewThermalZones() ([]ThermalZone, error) {
+	matches, err := filepath.Glob(fs.Path("class/thermal/thermal_zone[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+	sortThermalPaths(matches, "thermal_zone")
+
+	zones := make([]ThermalZone, 0, len(matches))
+	for _, m := range matches {
+		z, err := parseThermalZone(m)
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, z)
+	}
+
+	return zones, nil
+}
+
+// sortThermalPaths sorts the paths of numbered directories like
+// thermal_zone10 after thermal_zone2, unlike Glob.
+func sortThermalPaths(paths []string, prefix string) {
+	sort.Slice(paths, func(i, j int) bool {
+		a, _ := strconv.Atoi(strings.TrimPrefix(filepath.Base(paths[i]), prefix))
+		b, _ := strconv.Atoi(strings.TrimPrefix(filepath.Base(paths[j]), prefix))
+		return a < b
+	})
+}
+
+func parseCoolingDevice(path string) (CoolingDevice, error) {
+	var (
+		d   = CoolingDevice{Name: filepath.Base(path)}
+		err error
+	)
+
+	if d.Type, err = readSysfsString(filepath.Join(path, "type")); err != nil {
+		return CoolingDevice{}, err
+	}
+	if d.CurState, err = readSysfsInt(filepath.Join(path, "cur_state")); err != nil {
+		return CoolingDevice{}, err
+	}
+	if d.MaxState, err = readSysfsInt(filepath.Join(path, "max_state")); err != nil {
+		return CoolingDevice{}, err
+	}
+
+	return d, nil
+}
+
+func parseThermalZone(path string) (ThermalZone, error) {
+	var (
+		z   = ThermalZone{Name: filepath.Base(path)}
+		err error
+	)
+
+	if z.Type, err = readSysfsString(filepath.Join(path, "type")); err != nil {
+		return ThermalZone{}, err
+	}
+	if z.Temp, err = readSysfsInt(filepath.Join(path, "temp")); err != nil {
+		return ThermalZone{}, err
+	}
+	if z.Policy, err = readSysfsString(filepath.Join(path, "policy")); err != nil {
+		return ThermalZone{}, err
+	}
+	if z.TripPoints, err = parseThermalZoneTripPoints(path); err != nil {
+		return ThermalZone{}, err
+	}
+
+	matches, err := filepath.Glob(filepath.Join(path, "cdev*"))
+	if err != nil {
+		return ThermalZone{}, err
+	}
+	for _, m := range matches {
+		// Skip the cdev<N>_trip_point and cdev<N>_weight attributes.
+		cdev := filepath.Base(m)
+		if !thermalZoneCdevRE.MatchString(cdev) {
+			continue
+		}
+
+		target, err := os.Readlink(m)
+		if err != nil {
+			return ThermalZone{}, err
+		}
+		trip, err := readSysfsInt(m + "_trip_point")
+		if err != nil {
+			return ThermalZone{}, err
+		}
+
+		z.CoolingDevices = append(z.CoolingDevices, ThermalZoneCoolingDevice{
+			Device:    filepath.Base(target),
+			TripPoint: trip,
+		})
+	}
+
+	return z, nil
+}
+
+func parseThermalZoneTripPoints(path string) ([]ThermalZoneTripPoint, error) {
+	matches, err := filepath.Glob(filepath.Join(path, "trip_point_*_type"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Glob sorts lexically, which puts trip_point_10 before trip_point_2.
+	numbers := make([]int, 0, len(matches))
+	for _, m := range matches {
+		match := thermalZoneTripRE.FindStringSubmatch(filepath.Base(m))
+		if match == nil {
+			continue
+		}
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, err
+		}
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+
+	trips := make([]ThermalZoneTripPoint, 0, len(numbers))
+	for _, n := range numbers {
+		prefix := filepath.Join(path, "trip_point_"+strconv.Itoa(n))
+
+		t, err := readSysfsString(prefix + "_type")
+		if err != nil {
+			return nil, err
+		}
+		temp, err := readSysfsInt(prefix + "_temp")
+		if err != nil {
+			return nil, err
+		}
+		trips = append(trips, ThermalZoneTripPoint{Type: t, Temp: temp})
+	}
+
+	return trips, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewCoolingDevices(t *testing.T) {
+	devices, err := FS("fixtures").NewCoolingDevices()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []CoolingDevice{
+		{Name: "cooling_device0", Type: "Fan", CurState: 2, MaxState: 5},
+		{Name: "cooling_device1", Type: "Processor", CurState: 0, MaxState: 3},
+		{Name: "cooling_device2", Type: "Processor", CurState: 0, MaxState: 3},
+		{Name: "cooling_device10", Type: "intel_powerclamp", CurState: 0, MaxState: 50},
+	}
+	if !reflect.DeepEqual(want, devices) {
+		t.Errorf("want cooling devices %v, have %v", want, devices)
+	}
+}
+
+func TestNewThermalZonesCoolingDevices(t *testing.T) {
+	zones, err := FS("fixtures").NewThermalZones()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 4, len(zones); want != have {
+		t.Fatalf("want %d thermal zones, have %d", want, have)
+	}
+
+	want := []ThermalZoneCoolingDevice{
+		{Device: "cooling_device0", TripPoint: 0},
+		{Device: "cooling_device1", TripPoint: 1},
+	}
+	if have := zones[0].CoolingDevices; !reflect.DeepEqual(want, have) {
+		t.Errorf("want %s cooling devices %v, have %v", zones[0].Name, want, have)
+	}
+}
+
+func TestNewThermalZones(t *testing.T) {
+	zones, err := FS("fixtures").NewThermalZones()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ThermalZone{
+		{
+			Name:   "thermal_zone0",
+			Type:   "acpitz",
+			Temp:   27800,
+			Policy: "step_wise",
+			TripPoints: []ThermalZoneTripPoint{
+				{Type: "critical", Temp: 105000},
+				{Type: "passive", Temp: 95000},
+			},
+		},
+		{
+			Name:       "thermal_zone1",
+			Type:       "x86_pkg_temp",
+			Temp:       52000,
+			Policy:     "step_wise",
+			TripPoints: []ThermalZoneTripPoint{},
+		},
+		{
+			Name:       "thermal_zone2",
+			Type:       "pch_cannonlake",
+			Temp:       45000,
+			Policy:     "step_wise",
+			TripPoints: []ThermalZoneTripPoint{},
+		},
+		{
+			Name:       "thermal_zone10",
+			Type:       "iwlwifi_1",
+			Temp:       40000,
+			Policy:     "step_wise",
+			TripPoints: []ThermalZoneTripPoint{},
+		},
+	}
+	for i := range zones {
+		// Cooling devices are covered by TestNewThermalZonesCoolingDevices.
+		zones[i].CoolingDevices = nil
+	}
+	if !reflect.DeepEqual(want, zones) {
+		t.Errorf("want thermal zones %+v, have %+v", want, zones)
+	}
+}
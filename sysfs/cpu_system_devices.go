@@ -0,0 +1,879 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CPUInfo contains information about the online CPUs of the system, read from
+// /sys/devices/system/cpu. The maps are keyed by logical CPU id.
+type CPUInfo struct {
+	// Logical ids of the online CPUs.
+	Online []int64
+	// Topology of each online CPU.
+	CPUTopologies map[int64]CPUTopology
+	// Frequency scaling settings of each online CPU. CPUs without cpufreq
+	// support, e.g. in most VMs, are missing.
+	CPUFreqs map[int64]CPUFreq
+	// Thermal throttle counters of each online CPU. Only available on x86
+	// CPUs with thermal monitoring.
+	CPUThermalThrottles map[int64]CPUThermalThrottle
+	// Idle states of each online CPU, ordered by state number. Missing if
+	// cpuidle is disabled.
+	CPUIdleStates map[int64][]CPUIdleState
+	// Caches of each online CPU, ordered by index. Missing if the
+	// architecture doesn't expose cache information.
+	CPUCaches map[int64][]CPUCache
+}
+
+// LogicalCPU bundles the information of CPUInfo about a single CPU.
+type LogicalCPU struct {
+	// Logical id of the CPU.
+	ID              int64
+	Topology        CPUTopology
+	Freq            CPUFreq
+	ThermalThrottle CPUThermalThrottle
+}
+
+// CPUTopology contains the topology of a single logical CPU, as exposed in
+// /sys/devices/system/cpu/cpu<N>/topology. The sibling lists use the kernel's
+// CPU list format, e.g. "0-3,8".
+type CPUTopology struct {
+	// Id of the physical core the CPU belongs to.
+	CoreID int64
+	// Id of the physical package (socket) the CPU belongs to.
+	PhysicalPackageID int64
+	// CPUs within the same core. Named thread_siblings_list before kernel
+	// 5.7, filled from CoreCPUsList on newer kernels.
+	ThreadSiblingsList string
+	// CPUs within the same package. Named core_siblings_list before kernel
+	// 5.7, filled from PackageCPUsList on newer kernels.
+	CoreSiblingsList string
+	// CPUs within the same core, filled from ThreadSiblingsList on older
+	// kernels.
+	CoreCPUsList string
+	// CPUs within the same package, filled from CoreSiblingsList on older
+	// kernels.
+	PackageCPUsList string
+}
+
+// CPUFreq contains the frequency scaling settings of a single CPU, as exposed
+// in /sys/devices/system/cpu/cpu<N>/cpufreq.
+type CPUFreq struct {
+	// Current frequency in kHz, as determined by the governor and driver.
+	ScalingCurFreq int64
+	// Minimum frequency in kHz the governor may select.
+	ScalingMinFreq int64
+	// Maximum frequency in kHz the governor may select.
+	ScalingMaxFreq int64
+	// The active scaling governor.
+	ScalingGovernor string
+	// Whitespace separated list of the governors available for the CPU.
+	ScalingAvailableGovernors string
+	// Discrete frequencies in kHz the CPU can be set to. Only exposed by
+	// drivers with a frequency table like acpi-cpufreq, nil otherwise.
+	ScalingAvailableFrequencies []int64
+	// Energy performance preference (EPP) hint of the CPU, e.g.
+	// "balance_performance". Only exposed by intel_pstate and amd-pstate,
+	// empty otherwise.
+	EnergyPerformancePreference string
+	// Energy performance preferences the CPU accepts.
+	EnergyPerformanceAvailablePreferences []string
+	// The cpufreq driver in use.
+	ScalingDriver string
+}
+
+// CPUThermalThrottle contains the thermal throttle counters of a single CPU,
+// as exposed in /sys/devices/system/cpu/cpu<N>/thermal_throttle.
+type CPUThermalThrottle struct {
+	// Number of times the core of the CPU was throttled.
+	CoreThrottleCount int64
+	// Number of times the package of the CPU was throttled.
+	PackageThrottleCount int64
+}
+
+// CPUCache contains info about a single cache of a CPU, as exposed in
+// /sys/devices/system/cpu/cpu<N>/cache/index<M>.
+type CPUCache struct {
+	// Level of the cache, e.g. 3 for the L3 cache.
+	Level int64
+	// Type of the cache, "Data", "Instruction" or "Unified".
+	Type string
+	// Size of the cache in bytes.
+	Size int64
+	// CPUs sharing the cache.
+	SharedCPUList []int64
+}
+
+// CPUIdleState contains the statistics of a single idle state (C-state) of a
+// CPU, as exposed in /sys/devices/system/cpu/cpu<N>/cpuidle/state<M>.
+type CPUIdleState struct {
+	// Name of the state, e.g. "C1".
+	Name string
+	// Description of the state.
+	Desc string
+	// Number of times the state was entered.
+	Usage uint64
+	// Total time spent in the state in microseconds.
+	Time uint64
+	// Minimum time in microseconds the CPU has to stay in the state for it
+	// to pay off.
+	Residency uint64
+	// Whether the state is disabled.
+	Disable bool
+}
+
+// CPUFreqPolicy contains the cpufreq settings shared by a group of CPUs, as
+// exposed in /sys/devices/system/cpu/cpufreq/policy*.
+type CPUFreqPolicy struct {
+	// Name of the policy directory, e.g. "policy0".
+	Name string
+	// CPUs which are managed by this policy.
+	AffectedCPUs []int64
+	// The active scaling governor.
+	ScalingGovernor string
+	// Current frequency in kHz, as determined by the governor and driver.
+	ScalingCurFreq int64
+	// The cpufreq driver in use.
+	ScalingDriver string
+}
+
+// CPUIdleInfo contains the cpuidle settings of the system, as exposed in
+// /sys/devices/system/cpu/cpuidle.
+type CPUIdleInfo struct {
+	// The active cpuidle driver, e.g. "intel_idle" or "acpi_idle".
+	Driver string
+	// The active cpuidle governor, e.g. "menu", "teo" or "ladder".
+	Governor string
+}
+
+// NewCPUInfo returns information about the online CPUs of the system.
+func NewCPUInfo() (CPUInfo, error) {
+	return NewCPUInfoAt(DefaultMountPoint)
+}
+
+// NewCPUInfoAt returns information about the online CPUs read from the sys
+// filesystem mounted at mountPoint, e.g. the one of the host mounted into a
+// container. It will error if the mount point isn't a readable directory.
+func NewCPUInfoAt(mountPoint string) (CPUInfo, error) {
+	fs, err := NewFS(mountPoint)
+	if err != nil {
+		return CPUInfo{}, err
+	}
+
+	return fs.NewCPUInfo()
+}
+
+// NewCPUInfo returns information about the online CPUs read from the
+// specified `sys` filesystem.
+func (fs FS) NewCPUInfo() (CPUInfo, error) {
+	return fs.NewCPUInfoContext(context.Background())
+}
+
+// NewCPUInfoContext is like NewCPUInfo, but stops reading the remaining CPUs
+// once ctx is done and returns ctx.Err().
+func (fs FS) NewCPUInfoContext(ctx context.Context) (CPUInfo, error) {
+	online, err := readSysfsString(fs.Path("devices/system/cpu/online"))
+	if err != nil {
+		return CPUInfo{}, err
+	}
+
+	info := CPUInfo{
+		CPUTopologies:       map[int64]CPUTopology{},
+		CPUFreqs:            map[int64]CPUFreq{},
+		CPUThermalThrottles: map[int64]CPUThermalThrottle{},
+		CPUCaches:           map[int64][]CPUCache{},
+		CPUIdleStates:       map[int64][]CPUIdleState{},
+	}
+	if info.Online, err = parseCPURange(online); err != nil {
+		return CPUInfo{}, err
+	}
+
+	// The CPUs are read by a bounded pool of workers, as each of them needs
+	// dozens of reads, which adds up on machines with hundreds of CPUs.
+	var (
+		cpus    = make([]logicalCPUInfo, len(info.Online))
+		jobs    = make(chan int)
+		wg      sync.WaitGroup
+		workers = runtime.GOMAXPROCS(0)
+	)
+	if workers > len(cpus) {
+		workers = len(cpus)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				cpus[i] = parseLogicalCPUInfo(fs.Path("devices/system/cpu", fmt.Sprintf("cpu%d", info.Online[i])))
+			}
+		}()
+	}
+	// No further CPUs are handed out once ctx is done, the workers only
+	// finish the CPUs they already started.
+dispatch:
+	for i := range cpus {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err != nil {
+		return CPUInfo{}, err
+	}
+
+	// Results are merged in the order of the CPUs, so the error of the lowest
+	// failing CPU is returned.
+	for i, cpu := range info.Online {
+		c := cpus[i]
+		if c.err != nil {
+			return CPUInfo{}, c.err
+		}
+		info.CPUTopologies[cpu] = c.topology
+		if c.freq != nil {
+			info.CPUFreqs[cpu] = *c.freq
+		}
+		if c.throttle != nil {
+			info.CPUThermalThrottles[cpu] = *c.throttle
+		}
+		if len(c.caches) > 0 {
+			info.CPUCaches[cpu] = c.caches
+		}
+		if len(c.idleStates) > 0 {
+			info.CPUIdleStates[cpu] = c.idleStates
+		}
+	}
+
+	return info, nil
+}
+
+// Governors returns the scaling governors available for the CPU.
+func (f CPUFreq) Governors() []string {
+	return strings.Fields(f.ScalingAvailableGovernors)
+}
+
+// LLCGroups returns the groups of online CPUs sharing a last level cache, i.e.
+// the cache with the highest level, ordered by their lowest CPU.
+func (info CPUInfo) LLCGroups() [][]int64 {
+	var (
+		groups [][]int64
+		seen   = map[string]bool{}
+	)
+	for _, cpu := range info.Online {
+		var llc *CPUCache
+		for i, c := range info.CPUCaches[cpu] {
+			if c.Type != "Instruction" && (llc == nil || c.Level > llc.Level) {
+				llc = &info.CPUCaches[cpu][i]
+			}
+		}
+		if llc == nil {
+			continue
+		}
+		key := fmt.Sprint(llc.SharedCPUList)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		groups = append(groups, llc.SharedCPUList)
+	}
+
+	return groups
+}
+
+// CPU returns the information about the CPU with the logical id n, and
+// whether it is online.
+func (info CPUInfo) CPU(n int64) (LogicalCPU, bool) {
+	t, ok := info.CPUTopologies[n]
+	if !ok {
+		return LogicalCPU{}, false
+	}
+
+	return LogicalCPU{
+		ID:              n,
+		Topology:        t,
+		Freq:            info.CPUFreqs[n],
+		ThermalThrottle: info.CPUThermalThrottles[n],
+	}, true
+}
+
+// CPUThrottleRate computes the core thermal throttle events per second of all
+// CPUs with throttle counters in both samples taken interval apart. CPUs which
+// went offline or came online in between, as well as CPUs whose counter was
+// reset, are skipped.
+func CPUThrottleRate(prev, cur CPUInfo, interval time.Duration) map[int64]float64 {
+	rates := map[int64]float64{}
+	if interval <= 0 {
+		return rates
+	}
+
+	for cpu, c := range cur.CPUThermalThrottles {
+		p, ok := prev.CPUThermalThrottles[cpu]
+		if !ok || c.CoreThrottleCount < p.CoreThrottleCount {
+			continue
+		}
+		rates[cpu] = float64(c.CoreThrottleCount-p.CoreThrottleCount) / interval.Seconds()
+	}
+
+	return rates
+}
+
+// OnlineChanged re-reads the online CPUs and compares them to a previously
+// read set, returning the CPUs which were brought online or taken offline in
+// the meantime as well as the current set.
+func (fs FS) OnlineChanged(previous []int64) (added, removed []int64, current []int64, err error) {
+	online, err := readSysfsString(fs.Path("devices/system/cpu/online"))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if current, err = parseCPURange(online); err != nil {
+		return nil, nil, nil, err
+	}
+
+	prev := make(map[int64]bool, len(previous))
+	for _, cpu := range previous {
+		prev[cpu] = true
+	}
+	cur := make(map[int64]bool, len(current))
+	for _, cpu := range current {
+		cur[cpu] = true
+		if !prev[cpu] {
+			added = append(added, cpu)
+		}
+	}
+	for _, cpu := range previous {
+		if !cur[cpu] {
+			removed = append(removed, cpu)
+		}
+	}
+
+	return added, removed, current, nil
+}
+
+// SetAllScalingGovernors sets the cpufreq scaling governor of all online CPUs
+// which support it, returning the CPUs whose governor was changed. CPUs which
+// already use the governor are left alone. Failures of single CPUs, including
+// CPUs which don't offer the governor, don't stop the remaining CPUs from
+// being set and are returned as one error. Requires root privileges.
+func (fs FS) SetAllScalingGovernors(governor string) (changed []int64, err error) {
+	online, err := readSysfsString(fs.Path("devices/system/cpu/online"))
+	if err != nil {
+		return nil, err
+	}
+	cpus, err := parseCPURange(online)
+	if err != nil {
+		return nil, err
+	}
+
+	// CPUs sharing a cpufreq policy change together, so read all current
+	// governors before writing any of them.
+	var (
+		current = make(map[int64]string, len(cpus))
+		errs    []string
+	)
+	for _, cpu := range cpus {
+		path := fs.Path("devices/system/cpu", fmt.Sprintf("cpu%d", cpu), "cpufreq")
+		f, err := parseCPUFreq(path)
+		switch {
+		case os.IsNotExist(err):
+			continue
+		case err != nil:
+			errs = append(errs, fmt.Sprintf("cpu%d: %s", cpu, err))
+			continue
+		}
+
+		if !f.hasGovernor(governor) {
+			errs = append(errs, fmt.Sprintf("cpu%d: governor not available", cpu))
+			continue
+		}
+
+		current[cpu] = f.ScalingGovernor
+	}
+
+	for _, cpu := range cpus {
+		g, ok := current[cpu]
+		if !ok || g == governor {
+			continue
+		}
+
+		path := fs.Path("devices/system/cpu", fmt.Sprintf("cpu%d", cpu), "cpufreq/scaling_governor")
+		if err := writeSysfsString(path, governor); err != nil {
+			errs = append(errs, fmt.Sprintf("cpu%d: %s", cpu, err))
+			continue
+		}
+		changed = append(changed, cpu)
+	}
+
+	if len(errs) > 0 {
+		return changed, fmt.Errorf("couldn't set scaling governor %s: %s", governor, strings.Join(errs, "; "))
+	}
+
+	return changed, nil
+}
+
+// SetScalingGovernor sets the cpufreq scaling governor of the given CPU, which
+// also changes it for all other CPUs sharing its cpufreq policy. It fails if
+// the CPU doesn't offer the governor. Requires root privileges.
+func (fs FS) SetScalingGovernor(cpu int64, governor string) error {
+	path := fs.Path("devices/system/cpu", fmt.Sprintf("cpu%d", cpu), "cpufreq")
+	f, err := parseCPUFreq(path)
+	if err != nil {
+		return err
+	}
+	if !f.hasGovernor(governor) {
+		return fmt.Errorf("scaling governor %s not available for cpu%d, available: %s", governor, cpu, f.ScalingAvailableGovernors)
+	}
+
+	err = writeSysfsString(filepath.Join(path, "scaling_governor"), governor)
+	switch {
+	case os.IsPermission(err):
+		return fmt.Errorf("couldn't set scaling governor of cpu%d, permission denied (root privileges required): %s", cpu, err)
+	case err != nil:
+		return fmt.Errorf("couldn't set scaling governor of cpu%d: %s", cpu, err)
+	}
+
+	return nil
+}
+
+// hasGovernor returns whether the governor is available for the CPU.
+func (f CPUFreq) hasGovernor(governor string) bool {
+	for _, g := range f.Governors() {
+		if g == governor {
+			return true
+		}
+	}
+	return false
+}
+
+// NewCPUIdleInfo reads the active cpuidle driver and governor.
+func (fs FS) NewCPUIdleInfo() (CPUIdleInfo, error) {
+	var (
+		path = fs.Path("devices/system/cpu/cpuidle")
+		info = CPUIdleInfo{}
+		err  error
+	)
+
+	if info.Driver, err = readSysfsString(filepath.Join(path, "current_driver")); err != nil {
+		return CPUIdleInfo{}, err
+	}
+
+	// current_governor_ro is available on all kernels, current_governor only
+	// on kernels booted with cpuidle_sysfs_switch or 5.6+.
+	for _, f := range []string{"current_governor_ro", "current_governor"} {
+		info.Governor, err = readSysfsString(filepath.Join(path, f))
+		if !os.IsNotExist(err) {
+			break
+		}
+	}
+	if err != nil {
+		return CPUIdleInfo{}, err
+	}
+
+	return info, nil
+}
+
+// NewCPUFreqPolicies reads the cpufreq policies of the system. Only available
+// on kernel 4.3+, older kernels only expose cpufreq per CPU.
+func (fs FS) NewCPUFreqPolicies() ([]CPUFreqPolicy, error) {
+	matches, err := filepath.Glob(fs.Path("devices/system/cpu/cpufreq/policy[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]CPUFreqPolicy, 0, len(matches))
+	for _, m := range matches {
+		p, err := parseCPUFreqPolicy(m)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+
+	return policies, nil
+}
+
+func parseCPUFreqPolicy(path string) (CPUFreqPolicy, error) {
+	var (
+		p   = CPUFreqPolicy{Name: filepath.Base(path)}
+		err error
+	)
+
+	affected, err := readSysfsString(filepath.Join(path, "affected_cpus"))
+	if err != nil {
+		return CPUFreqPolicy{}, err
+	}
+	for _, f := range strings.Fields(affected) {
+		cpu, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			return CPUFreqPolicy{}, fmt.Errorf("couldn't parse %s (affected_cpus): %s", f, err)
+		}
+		p.AffectedCPUs = append(p.AffectedCPUs, cpu)
+	}
+
+	if p.ScalingGovernor, err = readSysfsString(filepath.Join(path, "scaling_governor")); err != nil {
+		return CPUFreqPolicy{}, err
+	}
+	if p.ScalingCurFreq, err = readSysfsInt(filepath.Join(path, "scaling_cur_freq")); err != nil {
+		return CPUFreqPolicy{}, err
+	}
+	if p.ScalingDriver, err = readSysfsString(filepath.Join(path, "scaling_driver")); err != nil {
+		return CPUFreqPolicy{}, err
+	}
+
+	return p, nil
+}
+
+func parseCPUFreq(path string) (CPUFreq, error) {
+	var (
+		f   = CPUFreq{}
+		err error
+	)
+
+	if f.ScalingCurFreq, err = readSysfsInt(filepath.Join(path, "scaling_cur_freq")); err != nil {
+		return CPUFreq{}, err
+	}
+	if f.ScalingMinFreq, err = readSysfsInt(filepath.Join(path, "scaling_min_freq")); err != nil {
+		return CPUFreq{}, err
+	}
+	if f.ScalingMaxFreq, err = readSysfsInt(filepath.Join(path, "scaling_max_freq")); err != nil {
+		return CPUFreq{}, err
+	}
+	if f.ScalingGovernor, err = readSysfsString(filepath.Join(path, "scaling_governor")); err != nil {
+		return CPUFreq{}, err
+	}
+	if f.ScalingAvailableGovernors, err = readSysfsString(filepath.Join(path, "scaling_available_governors")); err != nil {
+		return CPUFreq{}, err
+	}
+	if f.ScalingDriver, err = readSysfsString(filepath.Join(path, "scaling_driver")); err != nil {
+		return CPUFreq{}, err
+	}
+
+	freqs, err := readSysfsString(filepath.Join(path, "scaling_available_frequencies"))
+	switch {
+	case err == nil:
+		for _, field := range strings.Fields(freqs) {
+			freq, err := strconv.ParseInt(field, 10, 64)
+			if err != nil {
+				return CPUFreq{}, fmt.Errorf("couldn't parse %s (scaling_available_frequencies): %s", field, err)
+			}
+			f.ScalingAvailableFrequencies = append(f.ScalingAvailableFrequencies, freq)
+		}
+	case !os.IsNotExist(err):
+		return CPUFreq{}, err
+	}
+
+	epp, err := readSysfsString(filepath.Join(path, "energy_performance_preference"))
+	switch {
+	case err == nil:
+		f.EnergyPerformancePreference = epp
+	case !os.IsNotExist(err):
+		return CPUFreq{}, err
+	}
+
+	epps, err := readSysfsString(filepath.Join(path, "energy_performance_available_preferences"))
+	switch {
+	case err == nil:
+		f.EnergyPerformanceAvailablePreferences = strings.Fields(epps)
+	case !os.IsNotExist(err):
+		return CPUFreq{}, err
+	}
+
+	return f, nil
+}
+
+// logicalCPUInfo holds the info read for a single CPU by NewCPUInfoContext.
+// Optional info missing for the CPU is nil.
+type logicalCPUInfo struct {
+	topology   CPUTopology
+	freq       *CPUFreq
+	throttle   *CPUThermalThrottle
+	caches     []CPUCache
+	idleStates []CPUIdleState
+	err        error
+}
+
+func parseLogicalCPUInfo(path string) logicalCPUInfo {
+	var (
+		c   = logicalCPUInfo{}
+		err error
+	)
+
+	if c.topology, err = parseCPUTopology(filepath.Join(path, "topology")); err != nil {
+		return logicalCPUInfo{err: err}
+	}
+
+	f, err := parseCPUFreq(filepath.Join(path, "cpufreq"))
+	switch {
+	case err == nil:
+		c.freq = &f
+	case !os.IsNotExist(err):
+		return logicalCPUInfo{err: err}
+	}
+
+	t, err := parseCPUThermalThrottle(filepath.Join(path, "thermal_throttle"))
+	switch {
+	case err == nil:
+		c.throttle = &t
+	case !os.IsNotExist(err):
+		return logicalCPUInfo{err: err}
+	}
+
+	if c.caches, err = parseCPUCaches(filepath.Join(path, "cache")); err != nil {
+		return logicalCPUInfo{err: err}
+	}
+
+	if c.idleStates, err = parseCPUIdle(path); err != nil {
+		return logicalCPUInfo{err: err}
+	}
+
+	return c
+}
+
+func parseCPUThermalThrottle(path string) (CPUThermalThrottle, error) {
+	var (
+		t   = CPUThermalThrottle{}
+		err error
+	)
+
+	if t.CoreThrottleCount, err = readSysfsInt(filepath.Join(path, "core_throttle_count")); err != nil {
+		return CPUThermalThrottle{}, err
+	}
+	if t.PackageThrottleCount, err = readSysfsInt(filepath.Join(path, "package_throttle_count")); err != nil {
+		return CPUThermalThrottle{}, err
+	}
+
+	return t, nil
+}
+
+func parseCPUCaches(path string) ([]CPUCache, error) {
+	matches, err := filepath.Glob(filepath.Join(path, "index[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Glob sorts lexically, which puts index10 before index2.
+	caches := make([]CPUCache, len(matches))
+	for _, m := range matches {
+		n, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(m), "index"))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (cache index): %s", filepath.Base(m), err)
+		}
+		if n >= len(caches) {
+			return nil, fmt.Errorf("non-contiguous cache indexes in %s", path)
+		}
+		if caches[n], err = parseCPUCache(m); err != nil {
+			return nil, err
+		}
+	}
+
+	return caches, nil
+}
+
+func parseCPUCache(path string) (CPUCache, error) {
+	var (
+		c   = CPUCache{}
+		err error
+	)
+
+	if c.Level, err = readSysfsInt(filepath.Join(path, "level")); err != nil {
+		return CPUCache{}, err
+	}
+	if c.Type, err = readSysfsString(filepath.Join(path, "type")); err != nil {
+		return CPUCache{}, err
+	}
+	shared, err := readSysfsString(filepath.Join(path, "shared_cpu_list"))
+	if err != nil {
+		return CPUCache{}, err
+	}
+	if c.SharedCPUList, err = parseCPURange(shared); err != nil {
+		return CPUCache{}, err
+	}
+
+	// The size is printed with a unit, e.g. "32K".
+	size, err := readSysfsString(filepath.Join(path, "size"))
+	if err != nil {
+		return CPUCache{}, err
+	}
+	unit := int64(1)
+	switch {
+	case strings.HasSuffix(size, "K"):
+		unit = 1024
+	case strings.HasSuffix(size, "M"):
+		unit = 1024 * 1024
+	}
+	if c.Size, err = strconv.ParseInt(strings.TrimRight(size, "KM"), 10, 64); err != nil {
+		return CPUCache{}, fmt.Errorf("couldn't parse %s (cache size): %s", size, err)
+	}
+	c.Size *= unit
+
+	return c, nil
+}
+
+func parseCPUIdle(path string) ([]CPUIdleState, error) {
+	matches, err := filepath.Glob(filepath.Join(path, "cpuidle/state[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	// Glob sorts lexically, which puts state10 before state2.
+	states := make([]CPUIdleState, len(matches))
+	for _, m := range matches {
+		n, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(m), "state"))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (cpuidle state): %s", filepath.Base(m), err)
+		}
+		if n >= len(states) {
+			return nil, fmt.Errorf("non-contiguous cpuidle states of %s", filepath.Base(path))
+		}
+		if states[n], err = parseCPUIdleState(m); err != nil {
+			return nil, err
+		}
+	}
+
+	return states, nil
+}
+
+func parseCPUIdleState(path string) (CPUIdleState, error) {
+	var (
+		s   = CPUIdleState{}
+		err error
+	)
+
+	if s.Name, err = readSysfsString(filepath.Join(path, "name")); err != nil {
+		return CPUIdleState{}, err
+	}
+	if s.Desc, err = readSysfsString(filepath.Join(path, "desc")); err != nil {
+		return CPUIdleState{}, err
+	}
+	for _, f := range []struct {
+		name string
+		v    *uint64
+	}{
+		{name: "usage", v: &s.Usage},
+		{name: "time", v: &s.Time},
+		{name: "residency", v: &s.Residency},
+	} {
+		v, err := readSysfsInt(filepath.Join(path, f.name))
+		if err != nil {
+			return CPUIdleState{}, err
+		}
+		*f.v = uint64(v)
+	}
+
+	disable, err := readSysfsInt(filepath.Join(path, "disable"))
+	if err != nil {
+		return CPUIdleState{}, err
+	}
+	s.Disable = disable == 1
+
+	return s, nil
+}
+
+func parseCPUTopology(path string) (CPUTopology, error) {
+	var (
+		t   = CPUTopology{}
+		err error
+	)
+
+	if t.CoreID, err = readSysfsInt(filepath.Join(path, "core_id")); err != nil {
+		return CPUTopology{}, err
+	}
+	if t.PhysicalPackageID, err = readSysfsInt(filepath.Join(path, "physical_package_id")); err != nil {
+		return CPUTopology{}, err
+	}
+
+	// Kernel 5.7 renamed the sibling lists, but kept the old names around for
+	// compatibility. Read whichever exist and fill in the others.
+	for _, f := range []struct {
+		name string
+		dst  *string
+	}{
+		{name: "thread_siblings_list", dst: &t.ThreadSiblingsList},
+		{name: "core_siblings_list", dst: &t.CoreSiblingsList},
+		{name: "core_cpus_list", dst: &t.CoreCPUsList},
+		{name: "package_cpus_list", dst: &t.PackageCPUsList},
+	} {
+		v, err := readSysfsString(filepath.Join(path, f.name))
+		if err != nil && !os.IsNotExist(err) {
+			return CPUTopology{}, err
+		}
+		*f.dst = v
+	}
+
+	if t.CoreCPUsList == "" {
+		t.CoreCPUsList = t.ThreadSiblingsList
+	}
+	if t.ThreadSiblingsList == "" {
+		t.ThreadSiblingsList = t.CoreCPUsList
+	}
+	if t.PackageCPUsList == "" {
+		t.PackageCPUsList = t.CoreSiblingsList
+	}
+	if t.CoreSiblingsList == "" {
+		t.CoreSiblingsList = t.PackageCPUsList
+	}
+
+	return t, nil
+}
+
+// parseCPURange parses a CPU list in the format used by the kernel, e.g.
+// "0-3,8,10-11", and returns the ids of all CPUs in it.
+func parseCPURange(data string) ([]int64, error) {
+	var cpus []int64
+
+	for _, r := range strings.Split(strings.TrimSpace(data), ",") {
+		if r == "" {
+			continue
+		}
+
+		bounds := strings.SplitN(r, "-", 2)
+		first, err := strconv.ParseInt(bounds[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse CPU range %q: %s", r, err)
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.ParseInt(bounds[1], 10, 64); err != nil {
+				return nil, fmt.Errorf("couldn't parse CPU range %q: %s", r, err)
+			}
+		}
+		if last < first {
+			return nil, fmt.Errorf("invalid CPU range %q", r)
+		}
+
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+
+	return cpus, nil
+}
@@ -0,0 +1,521 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewCPUFreqPolicies(t *testing.T) {
+	policies, err := FS("fixtures").NewCPUFreqPolicies()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 2, len(policies); want != have {
+		t.Fatalf("want %d policies, have %d", want, have)
+	}
+
+	for i, tt := range []struct {
+		name     string
+		affected []int64
+		governor string
+		curFreq  int64
+	}{
+		{name: "policy0", affected: []int64{0, 1}, governor: "powersave", curFreq: 1800000},
+		{name: "policy2", affected: []int64{2, 3}, governor: "performance", curFreq: 2400000},
+	} {
+		p := policies[i]
+		if want, have := tt.name, p.Name; want != have {
+			t.Errorf("want policy name %q, have %q", want, have)
+		}
+		if want, have := tt.affected, p.AffectedCPUs; !reflect.DeepEqual(want, have) {
+			t.Errorf("want %s affected cpus %v, have %v", tt.name, want, have)
+		}
+		if want, have := tt.governor, p.ScalingGovernor; want != have {
+			t.Errorf("want %s governor %q, have %q", tt.name, want, have)
+		}
+		if want, have := tt.curFreq, p.ScalingCurFreq; want != have {
+			t.Errorf("want %s current frequency %d, have %d", tt.name, want, have)
+		}
+		if want, have := "intel_pstate", p.ScalingDriver; want != have {
+			t.Errorf("want %s driver %q, have %q", tt.name, want, have)
+		}
+	}
+}
+
+func TestNewCPUInfoTopology(t *testing.T) {
+	info, err := FS("fixtures").NewCPUInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := []int64{0, 1, 2, 3}, info.Online; !reflect.DeepEqual(want, have) {
+		t.Fatalf("want online cpus %v, have %v", want, have)
+	}
+	if want, have := len(info.Online), len(info.CPUTopologies); want != have {
+		t.Fatalf("want topology for %d cpus, have %d", want, have)
+	}
+
+	// cpu0 only exposes the new names, cpu1 only the legacy ones, cpu2 and
+	// cpu3 expose both.
+	for i, tt := range []struct {
+		coreID int64
+		core   string
+		pkg    string
+	}{
+		{coreID: 0, core: "0,2", pkg: "0-3"},
+		{coreID: 1, core: "1,3", pkg: "0-3"},
+		{coreID: 0, core: "0,2", pkg: "0-3"},
+		{coreID: 1, core: "1,3", pkg: "0-3"},
+	} {
+		topo := info.CPUTopologies[int64(i)]
+		if want, have := tt.coreID, topo.CoreID; want != have {
+			t.Errorf("want cpu%d core id %d, have %d", i, want, have)
+		}
+		for _, list := range []struct {
+			name string
+			want string
+			have string
+		}{
+			{name: "thread_siblings_list", want: tt.core, have: topo.ThreadSiblingsList},
+			{name: "core_cpus_list", want: tt.core, have: topo.CoreCPUsList},
+			{name: "core_siblings_list", want: tt.pkg, have: topo.CoreSiblingsList},
+			{name: "package_cpus_list", want: tt.pkg, have: topo.PackageCPUsList},
+		} {
+			if list.want != list.have {
+				t.Errorf("want cpu%d %s %q, have %q", i, list.name, list.want, list.have)
+			}
+		}
+	}
+}
+
+func TestCPUInfoCPU(t *testing.T) {
+	info, err := FS("fixtures").NewCPUInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cpu, ok := info.CPU(2)
+	if !ok {
+		t.Fatal("want cpu2 to be online")
+	}
+	if want, have := int64(0), cpu.Topology.CoreID; want != have {
+		t.Errorf("want cpu2 core id %d, have %d", want, have)
+	}
+	if want, have := "performance", cpu.Freq.ScalingGovernor; want != have {
+		t.Errorf("want cpu2 governor %q, have %q", want, have)
+	}
+	if want, have := int64(3400000), cpu.Freq.ScalingMaxFreq; want != have {
+		t.Errorf("want cpu2 max frequency %d, have %d", want, have)
+	}
+	if want, have := []string{"performance", "powersave"}, cpu.Freq.Governors(); !reflect.DeepEqual(want, have) {
+		t.Errorf("want cpu2 governors %v, have %v", want, have)
+	}
+	if want, have := int64(12), cpu.ThermalThrottle.CoreThrottleCount; want != have {
+		t.Errorf("want cpu2 core throttle count %d, have %d", want, have)
+	}
+
+	if want, have := []int64{3400000, 2800000, 2200000, 1600000, 800000}, cpu.Freq.ScalingAvailableFrequencies; !reflect.DeepEqual(want, have) {
+		t.Errorf("want cpu2 available frequencies %v, have %v", want, have)
+	}
+	if freqs := info.CPUFreqs[0].ScalingAvailableFrequencies; freqs != nil {
+		t.Errorf("want no available frequencies for cpu0, have %v", freqs)
+	}
+
+	if want, have := "", cpu.Freq.EnergyPerformancePreference; want != have {
+		t.Errorf("want cpu2 energy performance preference %q, have %q", want, have)
+	}
+
+	cpu, _ = info.CPU(0)
+	if want, have := "balance_performance", cpu.Freq.EnergyPerformancePreference; want != have {
+		t.Errorf("want cpu0 energy performance preference %q, have %q", want, have)
+	}
+	if want, have := []string{"default", "performance", "balance_performance", "balance_power", "power"}, cpu.Freq.EnergyPerformanceAvailablePreferences; !reflect.DeepEqual(want, have) {
+		t.Errorf("want cpu0 energy performance preferences %v, have %v", want, have)
+	}
+
+	if _, ok := info.CPU(4); ok {
+		t.Error("want cpu4 to be offline")
+	}
+}
+
+func TestNewCPUInfoIdleStates(t *testing.T) {
+	info, err := FS("fixtures").NewCPUInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 1, len(info.CPUIdleStates); want != have {
+		t.Fatalf("want idle states for %d cpus, have %d", want, have)
+	}
+
+	want := []CPUIdleState{
+		{Name: "POLL", Desc: "CPUIDLE CORE POLL IDLE", Usage: 29, Time: 2380},
+		{Name: "C1", Desc: "MWAIT 0x00", Usage: 93104, Time: 3917253821, Residency: 2, Disable: true},
+	}
+	if have := info.CPUIdleStates[0]; !reflect.DeepEqual(want, have) {
+		t.Errorf("want cpu0 idle states %+v, have %+v", want, have)
+	}
+}
+
+func TestCPUInfoLLCGroups(t *testing.T) {
+	info, err := FS("fixtures").NewCPUInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []CPUCache{
+		{Level: 1, Type: "Data", Size: 32 * 1024, SharedCPUList: []int64{1, 3}},
+		{Level: 2, Type: "Unified", Size: 256 * 1024, SharedCPUList: []int64{1, 3}},
+		{Level: 3, Type: "Unified", Size: 8192 * 1024, SharedCPUList: []int64{0, 1}},
+	}
+	if have := info.CPUCaches[1]; !reflect.DeepEqual(want, have) {
+		t.Errorf("want cpu1 caches %+v, have %+v", want, have)
+	}
+
+	if want, have := [][]int64{{0, 1}, {2, 3}}, info.LLCGroups(); !reflect.DeepEqual(want, have) {
+		t.Errorf("want llc groups %v, have %v", want, have)
+	}
+}
+
+func TestNewCPUInfoSparse(t *testing.T) {
+	info, err := FS("fixtures/cpusparse").NewCPUInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []int64{0, 4, 8} {
+		cpu, ok := info.CPU(id)
+		if !ok {
+			t.Errorf("want cpu%d to be online", id)
+			continue
+		}
+		if want, have := id, cpu.Topology.CoreID; want != have {
+			t.Errorf("want cpu%d core id %d, have %d", id, want, have)
+		}
+	}
+	if want, have := 3, len(info.CPUTopologies); want != have {
+		t.Errorf("want topology for %d cpus, have %d", want, have)
+	}
+	if want, have := 0, len(info.CPUFreqs); want != have {
+		t.Errorf("want cpufreq for %d cpus, have %d", want, have)
+	}
+}
+
+// cancelAfterContext is canceled after its Err method was called n times.
+type cancelAfterContext struct {
+	context.Context
+	mu sync.Mutex
+	n  int
+}
+
+func (ctx *cancelAfterContext) Err() error {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+	if ctx.n--; ctx.n < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestNewCPUInfoAt(t *testing.T) {
+	info, err := NewCPUInfoAt("fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := []int64{0, 1, 2, 3}, info.Online; !reflect.DeepEqual(want, have) {
+		t.Errorf("want online CPUs %v, have %v", want, have)
+	}
+
+	for _, mountPoint := range []string{"fixtures/nonexistent", "doc.go"} {
+		if _, err := NewCPUInfoAt(mountPoint); err == nil {
+			t.Errorf("want error for mount point %s", mountPoint)
+		}
+	}
+}
+
+func TestNewCPUInfoContext(t *testing.T) {
+	// The context is canceled after reading the first two of four CPUs.
+	ctx := &cancelAfterContext{Context: context.Background(), n: 2}
+	if _, err := FS("fixtures").NewCPUInfoContext(ctx); err != context.Canceled {
+		t.Errorf("want error %v, have %v", context.Canceled, err)
+	}
+	if ctx.n != -1 {
+		t.Errorf("want scan aborted at the third CPU, have %d remaining checks", ctx.n)
+	}
+
+	ctx = &cancelAfterContext{Context: context.Background(), n: 4}
+	if _, err := FS("fixtures").NewCPUInfoContext(ctx); err != nil {
+		t.Errorf("want no error before cancellation, have %v", err)
+	}
+}
+
+func TestCPUThrottleRate(t *testing.T) {
+	prev, err := FS("fixtures").NewCPUInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// cpu2 was throttled 600 times and cpu3 went offline in the meantime.
+	cur, err := FS("fixtures/cputhrottle").NewCPUInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[int64]float64{0: 0, 1: 0, 2: 60}
+	if have := CPUThrottleRate(prev, cur, 10*time.Second); !reflect.DeepEqual(want, have) {
+		t.Errorf("want throttle rates %v, have %v", want, have)
+	}
+}
+
+func TestOnlineChanged(t *testing.T) {
+	added, removed, current, err := FS("fixtures").OnlineChanged([]int64{0, 1, 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		want []int64
+		have []int64
+	}{
+		{name: "added", want: []int64{2, 3}, have: added},
+		{name: "removed", want: []int64{4}, have: removed},
+		{name: "current", want: []int64{0, 1, 2, 3}, have: current},
+	} {
+		if !reflect.DeepEqual(tt.want, tt.have) {
+			t.Errorf("want %s cpus %v, have %v", tt.name, tt.want, tt.have)
+		}
+	}
+}
+
+func TestSetAllScalingGovernors(t *testing.T) {
+	fs := writableFixture(t, "devices/system/cpu")
+	defer os.RemoveAll(string(fs))
+
+	// policy0 (cpu0, cpu1) uses powersave, policy2 (cpu2, cpu3) performance.
+	changed, err := fs.SetAllScalingGovernors("performance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := []int64{0, 1}, changed; !reflect.DeepEqual(want, have) {
+		t.Errorf("want changed cpus %v, have %v", want, have)
+	}
+
+	info, err := fs.NewCPUInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cpu := range info.Online {
+		if want, have := "performance", info.CPUFreqs[cpu].ScalingGovernor; want != have {
+			t.Errorf("want cpu%d governor %q, have %q", cpu, want, have)
+		}
+	}
+
+	changed, err = fs.SetAllScalingGovernors("schedutil")
+	if err == nil || !strings.Contains(err.Error(), "cpu3: governor not available") {
+		t.Errorf("want error for unavailable governor, have %v", err)
+	}
+	if len(changed) != 0 {
+		t.Errorf("want no changed cpus, have %v", changed)
+	}
+}
+
+func TestSetScalingGovernor(t *testing.T) {
+	fs := writableFixture(t, "devices/system/cpu")
+	defer os.RemoveAll(string(fs))
+
+	// cpu2 shares policy2 with cpu3.
+	if err := fs.SetScalingGovernor(2, "powersave"); err != nil {
+		t.Fatal(err)
+	}
+	for _, cpu := range []string{"cpu2", "cpu3"} {
+		governor, err := readSysfsString(fs.Path("devices/system/cpu", cpu, "cpufreq/scaling_governor"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, have := "powersave", governor; want != have {
+			t.Errorf("want %s governor %q, have %q", cpu, want, have)
+		}
+	}
+
+	err := fs.SetScalingGovernor(0, "schedutil")
+	if err == nil || !strings.Contains(err.Error(), "not available") {
+		t.Errorf("want error for unavailable governor, have %v", err)
+	}
+	if err := fs.SetScalingGovernor(7, "powersave"); err == nil {
+		t.Error("want error for missing cpu")
+	}
+
+	// Read-only attributes can't be written, not even by root.
+	path := fs.Path("devices/system/cpu/cpufreq/policy0/scaling_governor")
+	if err := os.Chmod(path, 0444); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeSysfsString(path, "performance"); !os.IsPermission(err) {
+		t.Errorf("want permission error, have %v", err)
+	}
+	err = fs.SetScalingGovernor(0, "performance")
+	if err == nil || !strings.Contains(err.Error(), "permission denied") {
+		t.Errorf("want permission error, have %v", err)
+	}
+}
+
+// writableFixture copies the given directory of the sysfs fixtures, keeping
+// symlinks, to a temporary directory and returns it as FS. The caller has to
+// remove the directory.
+func writableFixture(t testing.TB, dir string) FS {
+	root, err := ioutil.TempDir("", "sysfs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := copyFixture(filepath.Join("fixtures", dir), filepath.Join(root, dir)); err != nil {
+		os.RemoveAll(root)
+		t.Fatal(err)
+	}
+
+	return FS(root)
+}
+
+// copyFixture recursively copies src to dst, keeping symlinks as they are.
+func copyFixture(src, dst string) error {
+	return filepath.Walk(src, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case fi.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case fi.IsDir():
+			return os.MkdirAll(target, 0755)
+		default:
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			return ioutil.WriteFile(target, data, 0644)
+		}
+	})
+}
+
+func TestNewCPUIdleInfo(t *testing.T) {
+	info, err := FS("fixtures").NewCPUIdleInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := "intel_idle", info.Driver; want != have {
+		t.Errorf("want cpuidle driver %q, have %q", want, have)
+	}
+	if want, have := "menu", info.Governor; want != have {
+		t.Errorf("want cpuidle governor %q, have %q", want, have)
+	}
+}
+
+func TestParseCPURange(t *testing.T) {
+	cpus, err := parseCPURange("0-3,8,10-11\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := []int64{0, 1, 2, 3, 8, 10, 11}, cpus; !reflect.DeepEqual(want, have) {
+		t.Errorf("want cpus %v, have %v", want, have)
+	}
+}
+
+func TestParseCPURangeErrors(t *testing.T) {
+	for _, r := range []string{"0-abc", "abc", "3-1", "0-1,x"} {
+		if _, err := parseCPURange(r); err == nil {
+			t.Errorf("want error for cpu range %q", r)
+		}
+	}
+
+	// A missing file must stay distinguishable from a malformed one.
+	_, err := FS("fixtures/class").NewCPUInfo()
+	if !os.IsNotExist(err) {
+		t.Errorf("want not exist error for missing online file, have %v", err)
+	}
+}
+
+func TestParseCPURangeSingleCPU(t *testing.T) {
+	// A single-core VM which had CPUs 2 and 3 hotplugged but not onlined.
+	fs := FS("fixtures/cpusingle")
+
+	info, err := fs.NewCPUInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := []int64{0}, info.Online; !reflect.DeepEqual(want, have) {
+		t.Errorf("want online cpus %v, have %v", want, have)
+	}
+
+	present, err := readSysfsString(fs.Path("devices/system/cpu/present"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cpus, err := parseCPURange(present)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := []int64{0, 2, 3}, cpus; !reflect.DeepEqual(want, have) {
+		t.Errorf("want present cpus %v, have %v", want, have)
+	}
+}
+
+func BenchmarkNewCPUInfo(b *testing.B) {
+	// Clone cpu0 of the fixtures to get a machine with 64 CPUs.
+	fs := writableFixture(b, "devices/system/cpu")
+	defer os.RemoveAll(string(fs))
+	for cpu := 4; cpu < 64; cpu++ {
+		src := fs.Path("devices/system/cpu/cpu0")
+		if err := copyFixture(src, fs.Path("devices/system/cpu", fmt.Sprintf("cpu%d", cpu))); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := writeSysfsString(fs.Path("devices/system/cpu/online"), "0-63"); err != nil {
+		b.Fatal(err)
+	}
+
+	for _, procs := range []int{1, 8} {
+		b.Run(fmt.Sprintf("workers=%d", procs), func(b *testing.B) {
+			defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(procs))
+			for i := 0; i < b.N; i++ {
+				if _, err := fs.NewCPUInfo(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
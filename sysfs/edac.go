@@ -0,0 +1,133 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// EDACController contains the error counters of a memory controller, as
+// exposed in /sys/devices/system/edac/mc/mc<N>.
+type EDACController struct {
+	// Name of the controller directory, e.g. "mc0".
+	Name string
+	// Name of the controller as given by the EDAC driver.
+	MCName string
+	// Number of correctable errors.
+	CECount int64
+	// Number of uncorrectable errors.
+	UECount int64
+	// Error counters of the memory modules of the controller. Newer drivers
+	// report them per DIMM, older ones per chip select row. Chip select rows
+	// are only reported if there are no DIMM entries.
+	Memory []EDACMemory
+}
+
+// EDACMemory contains the error counters of a single DIMM or chip select row.
+type EDACMemory struct {
+	// Name of the directory, e.g. "dimm0" or "csrow0".
+	Name string
+	// Label of the DIMM, usually its slot on the mainboard. Not available
+	// for chip select rows.
+	Label string
+	// Number of correctable errors.
+	CECount int64
+	// Number of uncorrectable errors.
+	UECount int64
+}
+
+// NewEDAC reads the error counters of all memory controllers. The result is
+// empty if no EDAC driver is loaded.
+func (fs FS) NewEDAC() ([]EDACController, error) {
+	matches, err := filepath.Glob(fs.Path("devices/system/edac/mc/mc[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+
+	controllers := make([]EDACController, 0, len(matches))
+	for _, m := range matches {
+		c, err := parseEDACController(m)
+		if err != nil {
+			return nil, err
+		}
+		controllers = append(controllers, c)
+	}
+
+	return controllers, nil
+}
+
+func parseEDACController(path string) (EDACController, error) {
+	var (
+		c   = EDACController{Name: filepath.Base(path)}
+		err error
+	)
+
+	if c.MCName, err = readSysfsString(filepath.Join(path, "mc_name")); err != nil {
+		return EDACController{}, err
+	}
+	if c.CECount, err = readSysfsInt(filepath.Join(path, "ce_count")); err != nil {
+		return EDACController{}, err
+	}
+	if c.UECount, err = readSysfsInt(filepath.Join(path, "ue_count")); err != nil {
+		return EDACController{}, err
+	}
+
+	// Drivers exposing dimm entries may also keep the legacy csrow entries,
+	// which describe the same memory, so the latter are only read as a
+	// fallback.
+	for _, kind := range []struct {
+		glob   string
+		prefix string
+	}{
+		{glob: "dimm[0-9]*", prefix: "dimm_"},
+		{glob: "csrow[0-9]*", prefix: ""},
+	} {
+		matches, err := filepath.Glob(filepath.Join(path, kind.glob))
+		if err != nil {
+			return EDACController{}, err
+		}
+		for _, m := range matches {
+			mem, err := parseEDACMemory(m, kind.prefix)
+			if err != nil {
+				return EDACController{}, err
+			}
+			c.Memory = append(c.Memory, mem)
+		}
+		if len(matches) > 0 {
+			break
+		}
+	}
+
+	return c, nil
+}
+
+func parseEDACMemory(path, prefix string) (EDACMemory, error) {
+	var (
+		mem = EDACMemory{Name: filepath.Base(path)}
+		err error
+	)
+
+	if mem.CECount, err = readSysfsInt(filepath.Join(path, prefix+"ce_count")); err != nil {
+		return EDACMemory{}, err
+	}
+	if mem.UECount, err = readSysfsInt(filepath.Join(path, prefix+"ue_count")); err != nil {
+		return EDACMemory{}, err
+	}
+	if mem.Label, err = readSysfsString(filepath.Join(path, "dimm_label")); err != nil && !os.IsNotExist(err) {
+		return EDACMemory{}, err
+	}
+
+	return mem, nil
+}
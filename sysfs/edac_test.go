@@ -0,0 +1,58 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewEDAC(t *testing.T) {
+	controllers, err := FS("fixtures").NewEDAC()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []EDACController{
+		{
+			Name:    "mc0",
+			MCName:  "Skylake Socket#0 IMC#0",
+			CECount: 3,
+			// The legacy csrow0 of mc0 is ignored in favour of the DIMMs.
+			Memory: []EDACMemory{
+				{Name: "dimm0", Label: "CPU_SrcID#0_MC#0_Chan#0_DIMM#0", CECount: 3},
+				{Name: "dimm1", Label: "CPU_SrcID#0_MC#0_Chan#1_DIMM#0"},
+			},
+		},
+		{
+			Name:    "mc1",
+			MCName:  "i5000",
+			UECount: 1,
+			Memory:  []EDACMemory{{Name: "csrow0", UECount: 1}},
+		},
+	}
+	if !reflect.DeepEqual(want, controllers) {
+		t.Errorf("want edac controllers %+v, have %+v", want, controllers)
+	}
+}
+
+func TestNewEDACMissing(t *testing.T) {
+	controllers, err := FS("fixtures/class").NewEDAC()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 0, len(controllers); want != have {
+		t.Errorf("want %d edac controllers, have %d", want, have)
+	}
+}
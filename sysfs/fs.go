@@ -15,8 +15,12 @@ package sysfs
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
 
 	"github.com/prometheus/procfs/bcache"
 	"github.com/prometheus/procfs/xfs"
@@ -106,3 +110,55 @@ func (fs FS) BcacheStats() ([]*bcache.Stats, error) {
 
 	return stats, nil
 }
+
+// readSysfsString returns the content of the given sysfs attribute file with
+// the trailing newline removed.
+func readSysfsString(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+// writeSysfsString writes the given value to an existing sysfs attribute file.
+// Like sysfs, it refuses to write files without any write permission bit even
+// for root.
+func writeSysfsString(path, value string) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if fi.Mode().Perm()&0222 == 0 {
+		return &os.PathError{Op: "open", Path: path, Err: syscall.EACCES}
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.WriteString(value); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// readSysfsInt returns the value of a sysfs attribute file holding a single
+// decimal integer.
+func readSysfsInt(path string) (int64, error) {
+	s, err := readSysfsString(path)
+	if err != nil {
+		return 0, err
+	}
+
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't parse %s: %s", path, err)
+	}
+
+	return i, nil
+}
@@ -13,15 +13,26 @@
 
 package sysfs
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestNewFS(t *testing.T) {
-	if _, err := NewFS("foobar"); err == nil {
-		t.Error("want NewFS to fail for non-existing mount point")
+	if _, err := NewFS("fixtures"); err != nil {
+		t.Errorf("want NewFS to succeed for fixtures, have %v", err)
 	}
 
-	if _, err := NewFS("doc.go"); err == nil {
-		t.Error("want NewFS to fail if mount point is not a directory")
+	for _, mountPoint := range []string{"/does/not/exist", "doc.go"} {
+		_, err := NewFS(mountPoint)
+		if err == nil {
+			t.Errorf("want NewFS to fail for %s", mountPoint)
+			continue
+		}
+		// The error names the offending mount point.
+		if !strings.Contains(err.Error(), mountPoint) {
+			t.Errorf("want error mentioning %s, have %v", mountPoint, err)
+		}
 	}
 }
 
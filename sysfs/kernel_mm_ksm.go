@@ -0,0 +1,72 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// KSM contains the kernel same-page merging statistics read from
+// /sys/kernel/mm/ksm.
+type KSM struct {
+	// Run state of the KSM daemon: 0 stopped, 1 running, 2 stopped and all
+	// merged pages unmerged.
+	Run int64
+	// Number of shared pages in use.
+	PagesShared int64
+	// Number of sites sharing the shared pages, i.e. how many pages are
+	// saved.
+	PagesSharing int64
+	// Number of pages unique but repeatedly checked for merging.
+	PagesUnshared int64
+	// Number of pages changing too fast to be merged.
+	PagesVolatile int64
+	// Number of times all mergeable areas have been scanned.
+	FullScans int64
+}
+
+// NewKSM reads the kernel same-page merging statistics. It returns an error
+// if the kernel was built without CONFIG_KSM.
+func (fs FS) NewKSM() (KSM, error) {
+	path := fs.Path("kernel/mm/ksm")
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return KSM{}, fmt.Errorf("ksm not available, kernel built without CONFIG_KSM: %s", err)
+		}
+		return KSM{}, err
+	}
+
+	ksm := KSM{}
+	for _, f := range []struct {
+		name string
+		dst  *int64
+	}{
+		{name: "run", dst: &ksm.Run},
+		{name: "pages_shared", dst: &ksm.PagesShared},
+		{name: "pages_sharing", dst: &ksm.PagesSharing},
+		{name: "pages_unshared", dst: &ksm.PagesUnshared},
+		{name: "pages_volatile", dst: &ksm.PagesVolatile},
+		{name: "full_scans", dst: &ksm.FullScans},
+	} {
+		v, err := readSysfsInt(filepath.Join(path, f.name))
+		if err != nil {
+			return KSM{}, err
+		}
+		*f.dst = v
+	}
+
+	return ksm, nil
+}
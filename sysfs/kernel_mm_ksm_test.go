@@ -0,0 +1,34 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import "testing"
+
+func TestNewKSM(t *testing.T) {
+	ksm, err := FS("fixtures").NewKSM()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := int64(35890), ksm.PagesSharing; want != have {
+		t.Errorf("want pages_sharing %d, have %d", want, have)
+	}
+	if want, have := int64(186), ksm.FullScans; want != have {
+		t.Errorf("want full_scans %d, have %d", want, have)
+	}
+
+	if _, err := FS("fixtures/class").NewKSM(); err == nil {
+		t.Error("want error for missing ksm directory")
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// NewModuleParameters returns the current values of the parameters of a
+// kernel module, read from /sys/module/<module>/parameters and keyed by
+// parameter name. Write-only parameters are skipped.
+func (fs FS) NewModuleParameters(module string) (map[string]string, error) {
+	path := fs.Path("module", module, "parameters")
+
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	params := make(map[string]string, len(files))
+	for _, f := range files {
+		// Reading write-only parameters fails even for root.
+		if f.Mode().Perm()&0444 == 0 {
+			continue
+		}
+		v, err := readSysfsString(filepath.Join(path, f.Name()))
+		if err != nil {
+			if os.IsPermission(err) {
+				continue
+			}
+			return nil, err
+		}
+		params[f.Name()] = v
+	}
+
+	return params, nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewModuleParameters(t *testing.T) {
+	params, err := FS("fixtures").NewModuleParameters("nvme_core")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 4, len(params); want != have {
+		t.Errorf("want %d parameters, have %d", want, have)
+	}
+	if want, have := "30", params["io_timeout"]; want != have {
+		t.Errorf("want io_timeout %q, have %q", want, have)
+	}
+	if want, have := "Y", params["multipath"]; want != have {
+		t.Errorf("want multipath %q, have %q", want, have)
+	}
+}
+
+func TestNewModuleParametersWriteOnly(t *testing.T) {
+	// hotmod is write-only.
+	params, err := FS("fixtures").NewModuleParameters("ipmi_si")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := map[string]string{"kipmid_max_busy_us": "0"}, params; !reflect.DeepEqual(want, have) {
+		t.Errorf("want parameters %v, have %v", want, have)
+	}
+}
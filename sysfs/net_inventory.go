@@ -0,0 +1,76 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"os"
+	"sort"
+
+	"github.com/prometheus/procfs"
+)
+
+// Interface combines the counters of /proc/net/dev with the attributes of
+// /sys/class/net/<iface> for a single network interface. Fields of a source
+// which doesn't know the interface are left zero.
+type Interface struct {
+	NetClassIface
+	// Kernel index of the interface.
+	Index int64
+	// Traffic counters from /proc/net/dev.
+	Counters procfs.NetDevLine
+}
+
+// NewInterfaceInventory returns all network interfaces known to either
+// /proc/net/dev of proc or /sys/class/net, sorted by name.
+func (fs FS) NewInterfaceInventory(proc procfs.FS) ([]Interface, error) {
+	dev, err := proc.NewNetDev()
+	if err != nil {
+		return nil, err
+	}
+
+	netClass, err := fs.NewNetClass()
+	if err != nil {
+		return nil, err
+	}
+
+	ifaces := make(map[string]Interface, len(netClass))
+	for name, class := range netClass {
+		iface := Interface{NetClassIface: class}
+		index, err := readSysfsInt(fs.Path("class/net", name, "ifindex"))
+		switch {
+		case err == nil:
+			iface.Index = index
+		case !os.IsNotExist(err):
+			return nil, err
+		}
+		ifaces[name] = iface
+	}
+	for name, line := range dev {
+		iface, ok := ifaces[name]
+		if !ok {
+			iface.Name = name
+			iface.NUMANode = -1
+		}
+		iface.Counters = line
+		ifaces[name] = iface
+	}
+
+	inventory := make([]Interface, 0, len(ifaces))
+	for _, iface := range ifaces {
+		inventory = append(inventory, iface)
+	}
+	sort.Slice(inventory, func(i, j int) bool { return inventory[i].Name < inventory[j].Name })
+
+	return inventory, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"testing"
+
+	"github.com/prometheus/procfs"
+)
+
+func TestNewInterfaceInventory(t *testing.T) {
+	inventory, err := FS("fixtures/inventory/sys").NewInterfaceInventory(procfs.FS("fixtures/inventory/proc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 4, len(inventory); want != have {
+		t.Fatalf("want %d interfaces, have %d", want, have)
+	}
+
+	// dummy0 is only known to sysfs, lo only to /proc/net/dev. The
+	// bonding_masters file isn't an interface.
+	for i, tt := range []struct {
+		name      string
+		index     int64
+		mac       string
+		operState string
+		speed     int64
+		driver    string
+		rxBytes   uint64
+	}{
+		{name: "dummy0", index: 8, mac: "e6:2b:8a:10:33:d4", operState: "down"},
+		{name: "eth0", index: 2, mac: "00:1b:21:3a:4c:5e", operState: "up", speed: 10000, driver: "ixgbe", rxBytes: 9836402519},
+		{name: "lo", rxBytes: 183920},
+		{name: "veth0", index: 7, mac: "3a:5f:0c:9e:71:02", operState: "up", rxBytes: 5472910},
+	} {
+		iface := inventory[i]
+		if want, have := tt.name, iface.Name; want != have {
+			t.Errorf("want interface %q, have %q", want, have)
+		}
+		if want, have := tt.index, iface.Index; want != have {
+			t.Errorf("want %s index %d, have %d", tt.name, want, have)
+		}
+		if want, have := tt.mac, iface.Address; want != have {
+			t.Errorf("want %s mac %q, have %q", tt.name, want, have)
+		}
+		if want, have := tt.operState, iface.OperState; want != have {
+			t.Errorf("want %s operstate %q, have %q", tt.name, want, have)
+		}
+		if want, have := tt.speed, iface.Speed; want != have {
+			t.Errorf("want %s speed %d, have %d", tt.name, want, have)
+		}
+		if want, have := tt.driver, iface.Driver; want != have {
+			t.Errorf("want %s driver %q, have %q", tt.name, want, have)
+		}
+		if want, have := tt.rxBytes, iface.Counters.RxBytes; want != have {
+			t.Errorf("want %s rx bytes %d, have %d", tt.name, want, have)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NUMANode contains the memory usage and allocation counters of a single NUMA
+// node, as exposed in /sys/devices/system/node/node<N>.
+type NUMANode struct {
+	// Number of the node.
+	ID int64
+	// Memory of the node in bytes.
+	MemTotal uint64
+	MemFree  uint64
+	MemUsed  uint64
+	// Pages allocated on this node as intended.
+	NUMAHit uint64
+	// Pages allocated on this node although another node was preferred.
+	NUMAMiss uint64
+	// Pages intended for this node but allocated on another node.
+	NUMAForeign uint64
+	// Pages of the interleave policy allocated on this node as intended.
+	InterleaveHit uint64
+	// Pages allocated on this node while the process ran on it.
+	LocalNode uint64
+	// Pages allocated on this node while the process ran on another node.
+	OtherNode uint64
+}
+
+// NewNUMANodes returns info for all NUMA nodes, ordered by node number. Only
+// node0 exists on systems without NUMA.
+func (fs FS) NewNUMANodes() ([]NUMANode, error) {
+	matches, err := filepath.Glob(fs.Path("devices/system/node/node[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]NUMANode, 0, len(matches))
+	for _, m := range matches {
+		n, err := parseNUMANode(m)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	return nodes, nil
+}
+
+func parseNUMANode(path string) (NUMANode, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(filepath.Base(path), "node"), 10, 64)
+	if err != nil {
+		return NUMANode{}, fmt.Errorf("couldn't parse %s (node): %s", filepath.Base(path), err)
+	}
+	n := NUMANode{ID: id}
+
+	data, err := ioutil.ReadFile(filepath.Join(path, "meminfo"))
+	if err != nil {
+		return NUMANode{}, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		// Lines look like "Node 0 MemTotal:       16303632 kB".
+		fields := strings.Fields(line)
+		if len(fields) != 5 || fields[4] != "kB" {
+			continue
+		}
+
+		var v *uint64
+		switch fields[2] {
+		case "MemTotal:":
+			v = &n.MemTotal
+		case "MemFree:":
+			v = &n.MemFree
+		case "MemUsed:":
+			v = &n.MemUsed
+		default:
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[3], 10, 64)
+		if err != nil {
+			return NUMANode{}, fmt.Errorf("couldn't parse %s (%s): %s", fields[3], fields[2], err)
+		}
+		*v = kb * 1024
+	}
+
+	data, err = ioutil.ReadFile(filepath.Join(path, "numastat"))
+	if err != nil {
+		return NUMANode{}, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		var v *uint64
+		switch fields[0] {
+		case "numa_hit":
+			v = &n.NUMAHit
+		case "numa_miss":
+			v = &n.NUMAMiss
+		case "numa_foreign":
+			v = &n.NUMAForeign
+		case "interleave_hit":
+			v = &n.InterleaveHit
+		case "local_node":
+			v = &n.LocalNode
+		case "other_node":
+			v = &n.OtherNode
+		default:
+			continue
+		}
+		if *v, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+			return NUMANode{}, fmt.Errorf("couldn't parse %s (%s): %s", fields[1], fields[0], err)
+		}
+	}
+
+	return n, nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysfs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewNUMANodes(t *testing.T) {
+	nodes, err := FS("fixtures").NewNUMANodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []NUMANode{
+		{
+			ID:            0,
+			MemTotal:      16303632 * 1024,
+			MemFree:       8462684 * 1024,
+			MemUsed:       7840948 * 1024,
+			NUMAHit:       193460335,
+			NUMAMiss:      12624528,
+			NUMAForeign:   59858623,
+			InterleaveHit: 57146,
+			LocalNode:     193454780,
+			OtherNode:     12630083,
+		},
+		{
+			ID:            1,
+			MemTotal:      16513092 * 1024,
+			MemFree:       512344 * 1024,
+			MemUsed:       16000748 * 1024,
+			NUMAHit:       326720946,
+			NUMAMiss:      59858626,
+			NUMAForeign:   12624528,
+			InterleaveHit: 57286,
+			LocalNode:     326719046,
+			OtherNode:     59860526,
+		},
+	}
+	if !reflect.DeepEqual(want, nodes) {
+		t.Errorf("want numa nodes %+v, have %+v", want, nodes)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BootTime returns the time the system was booted, as read from the btime
+// line of /proc/stat.
+func BootTime() (time.Time, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return fs.BootTime()
+}
+
+// BootTime returns the time the system was booted, as read from the
+// specified `proc` filesystem.
+func (fs FS) BootTime() (time.Time, error) {
+	stat, err := fs.NewStat()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(int64(stat.BootTime), 0), nil
+}
+
+// Uptime returns the time elapsed since boot, as read from /proc/uptime.
+func Uptime() (time.Duration, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return 0, err
+	}
+
+	return fs.Uptime()
+}
+
+// Uptime returns the time elapsed since boot, as read from the specified
+// `proc` filesystem.
+func (fs FS) Uptime() (time.Duration, error) {
+	data, err := ioutil.ReadFile(fs.Path("uptime"))
+	if err != nil {
+		return 0, err
+	}
+
+	// The file holds the uptime and the summed up idle time of all CPUs.
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("unexpected uptime format: %q", data)
+	}
+	secs, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't parse %s (uptime): %s", fields[0], err)
+	}
+
+	return time.Duration(secs * float64(time.Second)), nil
+}
+
+// UptimeConsistent reports whether the boot time plus the uptime is within
+// threshold of now. The boot time is derived from the wall clock while the
+// uptime is monotonic, so a large difference points at clock adjustments
+// since boot. Since the boot time has a resolution of one second, threshold
+// should be at least that.
+func (fs FS) UptimeConsistent(now time.Time, threshold time.Duration) (bool, error) {
+	boot, err := fs.BootTime()
+	if err != nil {
+		return false, err
+	}
+	uptime, err := fs.Uptime()
+	if err != nil {
+		return false, err
+	}
+
+	skew := now.Sub(boot.Add(uptime))
+	if skew < 0 {
+		skew = -skew
+	}
+
+	return skew <= threshold, nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBootTime(t *testing.T) {
+	boot, err := FS("fixtures").BootTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := time.Unix(1418183276, 0), boot; !want.Equal(have) {
+		t.Errorf("want boot time %s, have %s", want, have)
+	}
+}
+
+func TestUptime(t *testing.T) {
+	uptime, err := FS("fixtures").Uptime()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 2372530*time.Millisecond, uptime; want != have {
+		t.Errorf("want uptime %s, have %s", want, have)
+	}
+}
+
+func TestUptimeConsistent(t *testing.T) {
+	now := time.Unix(1418183276, 0).Add(2372 * time.Second)
+
+	for _, tt := range []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{name: "consistent", now: now, want: true},
+		{name: "clock stepped forward", now: now.Add(time.Minute), want: false},
+		{name: "clock stepped back", now: now.Add(-time.Minute), want: false},
+	} {
+		have, err := FS("fixtures").UptimeConsistent(tt.now, 5*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.want != have {
+			t.Errorf("want %s uptime consistency %t, have %t", tt.name, tt.want, have)
+		}
+	}
+}
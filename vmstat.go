@@ -0,0 +1,128 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VMStat holds the virtual memory statistics of /proc/vmstat, keyed by
+// counter name. The set of counters varies between kernel versions.
+type VMStat map[string]uint64
+
+// VMStatRate holds the paging activity per second between two samples of
+// /proc/vmstat.
+type VMStatRate struct {
+	// Major page faults, which required loading a page from disk.
+	PgMajFault float64
+	// Pages scanned for reclaim by kswapd and direct reclaim.
+	PgScan float64
+	// Pages reclaimed by kswapd and direct reclaim.
+	PgSteal float64
+	// Pages swapped in.
+	PSwpIn float64
+	// Pages swapped out.
+	PSwpOut float64
+}
+
+// NewVMStat reads the virtual memory statistics from /proc/vmstat.
+func NewVMStat() (VMStat, error) {
+	fs, err := NewFS(DefaultMountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewVMStat()
+}
+
+// NewVMStat reads the virtual memory statistics from the specified `proc`
+// filesystem.
+func (fs FS) NewVMStat() (VMStat, error) {
+	f, err := os.Open(fs.Path("vmstat"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseVMStat(f)
+}
+
+// VMStatRates computes the paging rates between two samples of /proc/vmstat
+// taken interval apart. Counters missing from a sample are treated as zero.
+func VMStatRates(prev, cur VMStat, interval time.Duration) VMStatRate {
+	if interval <= 0 {
+		return VMStatRate{}
+	}
+
+	rate := func(p, c uint64) float64 {
+		if c < p {
+			return 0
+		}
+		return float64(c-p) / interval.Seconds()
+	}
+
+	return VMStatRate{
+		PgMajFault: rate(prev["pgmajfault"], cur["pgmajfault"]),
+		PgScan:     rate(prev.reclaimSum("pgscan"), cur.reclaimSum("pgscan")),
+		PgSteal:    rate(prev.reclaimSum("pgsteal"), cur.reclaimSum("pgsteal")),
+		PSwpIn:     rate(prev["pswpin"], cur["pswpin"]),
+		PSwpOut:    rate(prev["pswpout"], cur["pswpout"]),
+	}
+}
+
+// reclaimSum sums the kswapd and direct reclaim counters of the given kind.
+// Older kernels split them up by zone, e.g. "pgscan_kswapd_normal".
+func (s VMStat) reclaimSum(kind string) uint64 {
+	var sum uint64
+	for name, v := range s {
+		if !strings.HasPrefix(name, kind+"_kswapd") && !strings.HasPrefix(name, kind+"_direct") {
+			continue
+		}
+		// Counts how often direct reclaim was throttled, not pages.
+		if name == "pgscan_direct_throttle" {
+			continue
+		}
+		sum += v
+	}
+
+	return sum
+}
+
+func parseVMStat(r io.Reader) (VMStat, error) {
+	var (
+		stat    = VMStat{}
+		scanner = bufio.NewScanner(r)
+	)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid number of fields when parsing vmstat: %q", scanner.Text())
+		}
+
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (%s): %s", fields[1], fields[0], err)
+		}
+		stat[fields[0]] = v
+	}
+
+	return stat, scanner.Err()
+}
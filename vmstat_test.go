@@ -0,0 +1,74 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewVMStat(t *testing.T) {
+	stat, err := FS("fixtures").NewVMStat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]uint64{
+		"pgfault":  341640218,
+		"nr_dirty": 908,
+	} {
+		if have := stat[name]; want != have {
+			t.Errorf("want %s %d, have %d", name, want, have)
+		}
+	}
+
+	// Counters unknown to the kernel are missing rather than zero.
+	if _, ok := stat["nr_unstable"]; ok {
+		t.Error("want nr_unstable to be missing")
+	}
+}
+
+func TestVMStatRates(t *testing.T) {
+	prev, err := FS("fixtures").NewVMStat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cur := VMStat{
+		"pgmajfault":             prev["pgmajfault"] + 500,
+		"pgscan_kswapd":          prev["pgscan_kswapd"] + 800,
+		"pgscan_direct":          prev["pgscan_direct"] + 200,
+		"pgscan_direct_throttle": 7,
+		"pgsteal_kswapd":         prev["pgsteal_kswapd"] + 600,
+		"pgsteal_direct":         prev["pgsteal_direct"] + 150,
+		"pswpin":                 prev["pswpin"],
+	}
+	rates := VMStatRates(prev, cur, 10*time.Second)
+
+	for _, tt := range []struct {
+		name string
+		want float64
+		have float64
+	}{
+		{name: "pgmajfault", want: 50, have: rates.PgMajFault},
+		{name: "pgscan", want: 100, have: rates.PgScan},
+		{name: "pgsteal", want: 75, have: rates.PgSteal},
+		{name: "pswpin", want: 0, have: rates.PSwpIn},
+		{name: "pswpout", want: 0, have: rates.PSwpOut},
+	} {
+		if tt.want != tt.have {
+			t.Errorf("want %s rate %f, have %f", tt.name, tt.want, tt.have)
+		}
+	}
+}
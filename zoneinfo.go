@@ -0,0 +1,192 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var zoneHeaderRE = regexp.MustCompile(`^Node (\d+), zone\s+(\w+)$`)
+
+// ZoneInfo holds info parsed from /proc/zoneinfo.
+type ZoneInfo struct {
+	// Name of the NUMA node.
+	Node string
+	// Name of the memory zone, e.g. DMA32 or Normal.
+	Zone string
+	// Counters of the "per-node stats" block. The kernel prints these once
+	// per node as part of its first zone, they are shared by all zones of the
+	// same node. Only available on kernel 4.8+.
+	NodeStats map[string]int64
+	// Counters of the zone itself, e.g. "min" or "nr_zone_active_file".
+	// Multi-word keys are joined with underscores, e.g. "pages_free".
+	Values map[string]float64
+	// Counters of the per-CPU pagesets, keyed by CPU id and counter name,
+	// e.g. "count", "high", "batch" or "vm_stats_threshold".
+	Pagesets map[int64]map[string]float64
+}
+
+// Reclaimable summarizes the LRU pages of a set of zones.
+type Reclaimable struct {
+	// Pages on the file LRU lists, which can be reclaimed under memory
+	// pressure by dropping or writing back the page cache.
+	Reclaimable uint64
+	// Pages on the anon LRU lists, which can only be reclaimed by swapping.
+	Anon uint64
+}
+
+// NewZoneInfo reads the zoneinfo statistics.
+func NewZoneInfo() ([]ZoneInfo, error) {
+	return NewZoneInfoAt(DefaultMountPoint)
+}
+
+// NewZoneInfoAt reads the zoneinfo statistics from the proc filesystem
+// mounted at mountPoint, e.g. the one of the host mounted into a container.
+// It will error if the mount point isn't a readable directory.
+func NewZoneInfoAt(mountPoint string) ([]ZoneInfo, error) {
+	fs, err := NewFS(mountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.NewZoneInfo()
+}
+
+// NewZoneInfo reads the zoneinfo statistics from the specified `proc`
+// filesystem.
+func (fs FS) NewZoneInfo() ([]ZoneInfo, error) {
+	f, err := os.Open(fs.Path("zoneinfo"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseZoneInfo(f)
+}
+
+// ReclaimableSummary sums the active and inactive file and anon pages of all
+// zones, giving a rough figure of how much memory can be reclaimed under
+// pressure.
+func ReclaimableSummary(zones []ZoneInfo) Reclaimable {
+	var r Reclaimable
+	for _, z := range zones {
+		r.Reclaimable += uint64(z.Values["nr_zone_active_file"] + z.Values["nr_zone_inactive_file"])
+		r.Anon += uint64(z.Values["nr_zone_active_anon"] + z.Values["nr_zone_inactive_anon"])
+	}
+
+	return r
+}
+
+func parseZoneInfo(r io.Reader) ([]ZoneInfo, error) {
+	var (
+		zoneInfo    = []ZoneInfo{}
+		scanner     = bufio.NewScanner(r)
+		nodeStats   map[string]int64
+		inNodeStats bool
+		pageset     map[string]float64
+		inPagesets  bool
+	)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if m := zoneHeaderRE.FindStringSubmatch(line); m != nil {
+			if len(zoneInfo) == 0 || zoneInfo[len(zoneInfo)-1].Node != m[1] {
+				nodeStats = map[string]int64{}
+			}
+			zoneInfo = append(zoneInfo, ZoneInfo{
+				Node:      m[1],
+				Zone:      m[2],
+				NodeStats: nodeStats,
+				Values:    map[string]float64{},
+				Pagesets:  map[int64]map[string]float64{},
+			})
+			inNodeStats, inPagesets, pageset = false, false, nil
+			continue
+		}
+		if len(zoneInfo) == 0 {
+			return nil, fmt.Errorf("unexpected line before first zone in zoneinfo: %q", line)
+		}
+
+		if line == "per-node stats" {
+			inNodeStats = true
+			continue
+		}
+
+		parts := strings.Fields(line)
+		if inNodeStats {
+			if len(parts) == 2 && parts[0] != "pages" {
+				v, err := strconv.ParseInt(parts[1], 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("couldn't parse %s (%s): %s", parts[1], parts[0], err)
+				}
+				nodeStats[parts[0]] = v
+				continue
+			}
+			// The per-node block ends where the zone's own counters begin.
+			inNodeStats = false
+		}
+
+		zone := &zoneInfo[len(zoneInfo)-1]
+		if line == "pagesets" {
+			inPagesets = true
+			continue
+		}
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("malformed zoneinfo line: %q", line)
+		}
+		// The protection array isn't a counter, e.g.
+		// "protection: (0, 2877, 7826, 7826, 7826)".
+		if parts[0] == "protection:" {
+			continue
+		}
+
+		// Multi-word keys like "pages free" or "vm stats threshold:" are
+		// joined with underscores.
+		key := strings.TrimSuffix(strings.Join(parts[:len(parts)-1], "_"), ":")
+		v, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse %s (%s): %s", parts[len(parts)-1], key, err)
+		}
+
+		if inPagesets {
+			switch key {
+			case "cpu":
+				pageset = map[string]float64{}
+				zone.Pagesets[int64(v)] = pageset
+				continue
+			case "node_unreclaimable", "start_pfn":
+				// The pagesets end with the next zone level counter.
+				inPagesets, pageset = false, nil
+			default:
+				// Any other key belongs to the current CPU, so fields added
+				// by newer kernels such as "high_min" stay in the pageset.
+				if pageset == nil {
+					return nil, fmt.Errorf("unexpected line before first cpu in pagesets: %q", line)
+				}
+				pageset[key] = v
+				continue
+			}
+		}
+		zone.Values[key] = v
+	}
+
+	return zoneInfo, scanner.Err()
+}
@@ -0,0 +1,147 @@
+// Copyright 2018 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfs
+
+import "testing"
+
+func TestZoneInfo(t *testing.T) {
+	zoneInfo, err := FS("fixtures").NewZoneInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := 4, len(zoneInfo); want != have {
+		t.Fatalf("want %d zones, have %d", want, have)
+	}
+
+	for i, zone := range []string{"DMA", "DMA32", "Normal", "Movable"} {
+		if want, have := "0", zoneInfo[i].Node; want != have {
+			t.Errorf("want zone %d node %s, have %s", i, want, have)
+		}
+		if want, have := zone, zoneInfo[i].Zone; want != have {
+			t.Errorf("want zone %d name %s, have %s", i, want, have)
+		}
+	}
+
+	if want, have := int64(466886), zoneInfo[0].NodeStats["workingset_refault"]; want != have {
+		t.Errorf("want workingset_refault %d, have %d", want, have)
+	}
+	if want, have := 30, len(zoneInfo[0].NodeStats); want != have {
+		t.Errorf("want %d per-node stats, have %d", want, have)
+	}
+
+	// The per-node stats are shared by all zones of the node.
+	if want, have := int64(908), zoneInfo[2].NodeStats["nr_dirty"]; want != have {
+		t.Errorf("want Normal zone nr_dirty %d, have %d", want, have)
+	}
+	if _, ok := zoneInfo[0].NodeStats["nr_free_pages"]; ok {
+		t.Error("want per-zone counter nr_free_pages to be excluded from per-node stats")
+	}
+}
+
+func TestReclaimableSummary(t *testing.T) {
+	zoneInfo, err := FS("fixtures").NewZoneInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, have := float64(19510), zoneInfo[1].Values["min"]; want != have {
+		t.Errorf("want DMA32 min %f, have %f", want, have)
+	}
+
+	r := ReclaimableSummary(zoneInfo)
+	if want, have := uint64(663186), r.Reclaimable; want != have {
+		t.Errorf("want reclaimable pages %d, have %d", want, have)
+	}
+	if want, have := uint64(778561), r.Anon; want != have {
+		t.Errorf("want anon pages %d, have %d", want, have)
+	}
+}
+
+func TestNewZoneInfoAt(t *testing.T) {
+	zoneInfo, err := NewZoneInfoAt("fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := 4, len(zoneInfo); want != have {
+		t.Errorf("want %d zones, have %d", want, have)
+	}
+
+	for _, mountPoint := range []string{"fixtures/nonexistent", "fixtures/zoneinfo"} {
+		if _, err := NewZoneInfoAt(mountPoint); err == nil {
+			t.Errorf("want error for mount point %s", mountPoint)
+		}
+	}
+}
+
+func TestZoneInfoValues(t *testing.T) {
+	zoneInfo, err := FS("fixtures").NewZoneInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dma32 := zoneInfo[1]
+	for _, tt := range []struct {
+		name string
+		want float64
+		have float64
+	}{
+		{name: "pages_free", want: 204252, have: dma32.Values["pages_free"]},
+		{name: "high", want: 22608, have: dma32.Values["high"]},
+		{name: "nr_free_pages", want: 204252, have: dma32.Values["nr_free_pages"]},
+		{name: "start_pfn", want: 4096, have: dma32.Values["start_pfn"]},
+		{name: "cpu 1 pageset count", want: 356, have: dma32.Pagesets[1]["count"]},
+		{name: "cpu 0 pageset high", want: 378, have: dma32.Pagesets[0]["high"]},
+		{name: "cpu 0 vm stats threshold", want: 48, have: dma32.Pagesets[0]["vm_stats_threshold"]},
+	} {
+		if tt.want != tt.have {
+			t.Errorf("want DMA32 %s %f, have %f", tt.name, tt.want, tt.have)
+		}
+	}
+	if want, have := 2, len(dma32.Pagesets); want != have {
+		t.Errorf("want %d pagesets, have %d", want, have)
+	}
+	if _, ok := dma32.Values["count"]; ok {
+		t.Error("want pageset counters to be excluded from zone values")
+	}
+}
+
+func TestZoneInfoPagesetHighMinMax(t *testing.T) {
+	zoneInfo, err := FS("fixtures").NewZoneInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Linux 6.7 added high_min and high_max to the per-CPU pagesets.
+	normal := zoneInfo[2]
+	for cpu := int64(0); cpu < 2; cpu++ {
+		if want, have := float64(126), normal.Pagesets[cpu]["high_min"]; want != have {
+			t.Errorf("want Normal cpu %d pageset high_min %f, have %f", cpu, want, have)
+		}
+		if want, have := float64(756), normal.Pagesets[cpu]["high_max"]; want != have {
+			t.Errorf("want Normal cpu %d pageset high_max %f, have %f", cpu, want, have)
+		}
+		if want, have := float64(63), normal.Pagesets[cpu]["batch"]; want != have {
+			t.Errorf("want Normal cpu %d pageset batch %f, have %f", cpu, want, have)
+		}
+	}
+	for _, key := range []string{"high_min", "high_max"} {
+		if _, ok := normal.Values[key]; ok {
+			t.Errorf("want pageset counter %s to be excluded from zone values", key)
+		}
+	}
+	if want, have := float64(1048576), normal.Values["start_pfn"]; want != have {
+		t.Errorf("want Normal start_pfn %f, have %f", want, have)
+	}
+}